@@ -0,0 +1,112 @@
+package lorg
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// helperFunctions contains names of functions marked by Helper.
+	helperFunctions = &sync.Map{}
+
+	// helperCallers contains program counters of Helper calls, so Helper
+	// doesn't resolve function name on every call.
+	helperCallers = &sync.Map{}
+
+	hasHelpers int32
+
+	// callerFrames contains frames of program counters resolved by caller.
+	callerFrames = &sync.Map{}
+)
+
+// Helper marks the calling function as a logging helper function, like
+// testing.T.Helper does. When logger determines caller of logging function
+// for `${file}` and `${line}` placeholders, helper functions are skipped:
+//
+//	func logRequest(request *http.Request) {
+//		lorg.Helper()
+//		lorg.Infof("%s %s", request.Method, request.URL)
+//	}
+//
+// Helper marks function globally, so it's skipped for all loggers.
+func Helper() {
+	var pcs [1]uintptr
+	if runtime.Callers(2, pcs[:]) == 0 {
+		return
+	}
+
+	if _, ok := helperCallers.Load(pcs[0]); ok {
+		return
+	}
+
+	frame, _ := runtime.CallersFrames(pcs[:]).Next()
+
+	helperFunctions.Store(frame.Function, struct{}{})
+	helperCallers.Store(pcs[0], struct{}{})
+
+	atomic.StoreInt32(&hasHelpers, 1)
+}
+
+// WithCallerSkip returns a child of given logger which skips given amount of
+// additional stack frames when determines caller of logging function, so
+// wrappers of logger can report their callers as callers of logging
+// function. Skips of nested calls of WithCallerSkip are summed up.
+func (log *Log) WithCallerSkip(skip int) *Log {
+	child := log.WithFields(nil)
+	child.callerSkip = log.callerSkip + skip
+
+	return child
+}
+
+// caller returns the frame at given depth of the stack which is not a helper
+// function, depth 0 is the function which called caller.
+func caller(skip int) runtime.Frame {
+	var pcs [32]uintptr
+
+	// walking the stack is expensive, so only one frame is taken if there
+	// are no helper functions
+	size := 1
+	if atomic.LoadInt32(&hasHelpers) != 0 {
+		size = len(pcs)
+	}
+
+	count := runtime.Callers(skip+2, pcs[:size])
+	if count == 0 {
+		return runtime.Frame{}
+	}
+
+	var frame runtime.Frame
+	for _, pc := range pcs[:count] {
+		frame = callerFrame(pc)
+		if !isHelper(frame.Function) {
+			break
+		}
+	}
+
+	return frame
+}
+
+// callerFrame returns frame of given program counter returned by
+// runtime.Callers, frames are cached, because resolving them allocates.
+func callerFrame(pc uintptr) runtime.Frame {
+	if frame, ok := callerFrames.Load(pc); ok {
+		return frame.(runtime.Frame)
+	}
+
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+
+	callerFrames.Store(pc, frame)
+
+	return frame
+}
+
+func isHelper(function string) bool {
+	if atomic.LoadInt32(&hasHelpers) == 0 {
+		return false
+	}
+
+	_, ok := helperFunctions.Load(function)
+
+	return ok
+}
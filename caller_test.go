@@ -0,0 +1,69 @@
+package lorg
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLog_WithCallerSkip_SkipsWrapperFrames(t *testing.T) {
+	test := assert.New(t)
+
+	buffer := &bytes.Buffer{}
+
+	log := NewLog()
+	log.SetOutput(buffer)
+	log.SetFormat(NewFormat(`${file}:${line} %s`))
+
+	wrapper := log.WithCallerSkip(1)
+	logWrapped := func(message string) {
+		wrapper.With("a", 1).Info(message)
+	}
+
+	_, file, line, _ := runtime.Caller(0)
+	logWrapped("wrapped")
+	log.Info("direct")
+
+	test.Equal(
+		callerString(file, line+1)+" wrapped\n"+
+			callerString(file, line+2)+" direct\n",
+		buffer.String(),
+	)
+}
+
+func TestHelper_MarksFunctionsSkippedByLogger(t *testing.T) {
+	test := assert.New(t)
+
+	buffer := &bytes.Buffer{}
+
+	log := NewLog()
+	log.SetOutput(buffer)
+	log.SetFormat(NewFormat(`${file}:${line} %s`))
+
+	_, file, line, _ := runtime.Caller(0)
+	testHelperOuter(log, "nested")
+	testHelperInner(log, "helper")
+
+	test.Equal(
+		callerString(file, line+1)+" nested\n"+
+			callerString(file, line+2)+" helper\n",
+		buffer.String(),
+	)
+}
+
+func testHelperOuter(log *Log, message string) {
+	Helper()
+	testHelperInner(log, message)
+}
+
+func testHelperInner(log *Log, message string) {
+	Helper()
+	log.Info(message)
+}
+
+func callerString(file string, line int) string {
+	return formatFile(file, "") + ":" + strconv.Itoa(line)
+}
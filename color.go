@@ -0,0 +1,216 @@
+package lorg
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// Palette describes colors of logging levels which are used by `${color}`
+// and `${colorlevel}` placeholders, colors are specified in the same manner
+// as the value of `${color}` placeholder: "bold:red".
+type Palette map[Level]string
+
+var (
+	// DefaultPalette is the palette which is used by PlaceholderColor and
+	// PlaceholderColorLevel.
+	DefaultPalette = Palette{
+		LevelFatal:   "bold:red",
+		LevelError:   "red",
+		LevelWarning: "yellow",
+		LevelInfo:    "blue",
+		LevelDebug:   "cyan",
+		LevelTrace:   "gray",
+	}
+
+	colorCodes = map[string]string{
+		"bold":      "1",
+		"dim":       "2",
+		"italic":    "3",
+		"underline": "4",
+		"black":     "30",
+		"red":       "31",
+		"green":     "32",
+		"yellow":    "33",
+		"blue":      "34",
+		"magenta":   "35",
+		"cyan":      "36",
+		"white":     "37",
+		"gray":      "90",
+		"bgblack":   "40",
+		"bgred":     "41",
+		"bggreen":   "42",
+		"bgyellow":  "43",
+		"bgblue":    "44",
+		"bgmagenta": "45",
+		"bgcyan":    "46",
+		"bgwhite":   "47",
+		"bggray":    "100",
+	}
+
+	// colorlessPlaceholders are used by Format instead of color placeholders
+	// if colors are disabled.
+	colorlessPlaceholders = map[string]Placeholder{
+		"color":      placeholderEmpty,
+		"reset":      placeholderEmpty,
+		"colorlevel": PlaceholderLevel,
+	}
+)
+
+const colorReset = "\x1b[0m"
+
+// PlaceholderColor returns ANSI escape sequence which sets color specified
+// in placeholder value or color of current logging level from DefaultPalette
+// if value is empty. Value is a colon-separated list of color names (black,
+// red, green, yellow, blue, magenta, cyan, white, gray, their bg-prefixed
+// background variants, bold, dim, italic and underline) or SGR codes.
+//
+// Colors are not rendered if output of the logger is not a terminal or
+// NO_COLOR environment variable is set, FORCE_COLOR environment variable
+// forces colors.
+//
+// Using: ${color}%s${reset}
+//
+//	${color:bold:green}%s${reset}
+//	${color:38;5;208}%s${reset}
+func PlaceholderColor(logLevel Level, value string) string {
+	return NewPlaceholderColor(DefaultPalette)(logLevel, value)
+}
+
+// PlaceholderReset returns ANSI escape sequence which resets colors set by
+// PlaceholderColor.
+//
+// Using: ${reset}
+func PlaceholderReset(_ Level, _ string) string {
+	return colorReset
+}
+
+// PlaceholderColorLevel returns level of current logging record colored
+// using DefaultPalette, it accepts the same options as PlaceholderLevel.
+//
+// Using: ${colorlevel:[%s]:right:true}
+func PlaceholderColorLevel(logLevel Level, value string) string {
+	return NewPlaceholderColorLevel(DefaultPalette)(logLevel, value)
+}
+
+// NewPlaceholderColor returns PlaceholderColor which uses given palette.
+func NewPlaceholderColor(palette Palette) Placeholder {
+	return func(logLevel Level, value string) string {
+		if value == "" {
+			value = palette[logLevel]
+		}
+
+		return colorSequence(value)
+	}
+}
+
+// NewPlaceholderColorLevel returns PlaceholderColorLevel which uses given
+// palette.
+func NewPlaceholderColorLevel(palette Palette) Placeholder {
+	return func(logLevel Level, value string) string {
+		color := colorSequence(palette[logLevel])
+		if color == "" {
+			return PlaceholderLevel(logLevel, value)
+		}
+
+		return color + PlaceholderLevel(logLevel, value) + colorReset
+	}
+}
+
+func colorSequence(color string) string {
+	if color == "" {
+		return ""
+	}
+
+	cached := cache.getString("colors", color)
+	if cached != "" {
+		return cached
+	}
+
+	codes := []string{}
+	for _, name := range strings.Split(color, ":") {
+		if code, ok := colorCodes[strings.ToLower(name)]; ok {
+			codes = append(codes, code)
+			continue
+		}
+
+		if name != "" && strings.Trim(name, "0123456789;") == "" {
+			codes = append(codes, name)
+		}
+	}
+
+	if len(codes) == 0 {
+		return ""
+	}
+
+	sequence := "\x1b[" + strings.Join(codes, ";") + "m"
+
+	cache.set(sequence, "colors", color)
+
+	return sequence
+}
+
+func placeholderEmpty(_ Level, _ string) string {
+	return ""
+}
+
+// isColorOutput reports whether colors should be rendered for given output:
+// NO_COLOR environment variable disables colors, FORCE_COLOR environment
+// variable enables colors, otherwise colors are enabled only if all writers
+// of the output are terminals.
+func isColorOutput(output io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	switch os.Getenv("FORCE_COLOR") {
+	case "", "0", "false", "no":
+	default:
+		return true
+	}
+
+	return isTerminal(output)
+}
+
+func isTerminal(writer io.Writer) bool {
+	switch writer := writer.(type) {
+	case *Output:
+		writer.mutex.Lock()
+		defer writer.mutex.Unlock()
+
+		writers := []io.Writer{}
+		for _, levelWriters := range writer.conditions {
+			writers = append(writers, levelWriters...)
+		}
+
+		for _, route := range writer.routes {
+			writers = append(writers, route.writers...)
+		}
+
+		writers = uniqueWriters(writers)
+		if len(writers) == 0 {
+			return false
+		}
+
+		for _, writer := range writers {
+			if !isTerminal(writer) {
+				return false
+			}
+		}
+
+		return true
+
+	case *AsyncOutput:
+		return isTerminal(writer.output)
+
+	case interface{ Stat() (os.FileInfo, error) }:
+		stat, err := writer.Stat()
+		if err != nil {
+			return false
+		}
+
+		return stat.Mode()&os.ModeCharDevice != 0
+	}
+
+	return false
+}
@@ -0,0 +1,80 @@
+package lorg
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlaceholderColor_ReturnsEscapeSequences(t *testing.T) {
+	test := assert.New(t)
+
+	test.Equal("\x1b[33m", PlaceholderColor(LevelWarning, ""))
+	test.Equal("\x1b[1;31m", PlaceholderColor(LevelFatal, ""))
+	test.Equal("\x1b[1;32;44m", PlaceholderColor(LevelInfo, "bold:green:bgblue"))
+	test.Equal("\x1b[38;5;208m", PlaceholderColor(LevelInfo, "38;5;208"))
+	test.Equal("", PlaceholderColor(LevelInfo, "rainbow"))
+	test.Equal("\x1b[0m", PlaceholderReset(LevelInfo, ""))
+
+	test.Equal(
+		"\x1b[34m   [INFO]\x1b[0m",
+		PlaceholderColorLevel(LevelInfo, `[%s]:right`),
+	)
+}
+
+func TestLog_SetOutput_DisablesColorsIfOutputIsNotTerminal(t *testing.T) {
+	test := assert.New(t)
+
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "")
+
+	var buffer bytes.Buffer
+
+	log := NewLog()
+	log.SetFormat(NewFormat(`${colorlevel} ${color:red}%s${reset}`))
+	log.SetOutput(&buffer)
+
+	log.Info("plain")
+
+	t.Setenv("FORCE_COLOR", "1")
+	log.SetOutput(&buffer)
+	log.NewChild().Info("colored")
+
+	t.Setenv("NO_COLOR", "1")
+	log.SetOutput(&buffer)
+	log.Info("plain")
+
+	test.Equal(
+		"INFO plain\n"+
+			"\x1b[34mINFO\x1b[0m \x1b[31mcolored\x1b[0m\n"+
+			"INFO plain\n",
+		buffer.String(),
+	)
+}
+
+func TestFormat_SetPalette_ChangesLevelColors(t *testing.T) {
+	test := assert.New(t)
+
+	format := NewFormat(`${color}${colorlevel}`)
+	format.SetPalette(Palette{LevelError: "magenta"})
+
+	test.Equal(
+		"\x1b[35m\x1b[35mERROR\x1b[0m", format.Render(LevelError, ""),
+	)
+	test.Equal("INFO", format.Render(LevelInfo, ""))
+}
+
+func TestIsTerminal_ReturnsFalseForFiles(t *testing.T) {
+	test := assert.New(t)
+
+	file, err := os.Create(filepath.Join(t.TempDir(), "log"))
+	test.NoError(err)
+	defer file.Close()
+
+	test.False(isTerminal(file))
+	test.False(isTerminal(NewOutput(file)))
+	test.False(isTerminal(&bytes.Buffer{}))
+}
@@ -0,0 +1,86 @@
+package lorg
+
+import (
+	"context"
+	"sync"
+)
+
+// ContextExtractor returns fields which should be attached to log records
+// logged with given context, for example request or trace identifiers.
+type ContextExtractor func(ctx context.Context) Fields
+
+type contextKey struct{}
+
+var (
+	contextExtractors      []ContextExtractor
+	contextExtractorsMutex = &sync.RWMutex{}
+)
+
+// NewContext returns copy of given context which carries given logger, the
+// logger can be retrieved using FromContext.
+func NewContext(ctx context.Context, log *Log) context.Context {
+	return context.WithValue(ctx, contextKey{}, log)
+}
+
+// FromContext returns logger which is carried by given context or package
+// logger if context doesn't carry a logger.
+func FromContext(ctx context.Context) *Log {
+	if ctx != nil {
+		if log, ok := ctx.Value(contextKey{}).(*Log); ok && log != nil {
+			return log
+		}
+	}
+
+	return logger
+}
+
+// AddContextExtractor registers given extractor, fields returned by all
+// registered extractors are attached to records logged using InfoContext-like
+// functions and SlogHandler, so they are rendered by `${fields}` placeholder
+// and structured formats:
+//
+//	lorg.AddContextExtractor(lorg.ContextValue("request", requestKey{}))
+func AddContextExtractor(extractor ContextExtractor) {
+	contextExtractorsMutex.Lock()
+	contextExtractors = append(contextExtractors, extractor)
+	contextExtractorsMutex.Unlock()
+}
+
+// ResetContextExtractors removes all registered extractors.
+func ResetContextExtractors() {
+	contextExtractorsMutex.Lock()
+	contextExtractors = nil
+	contextExtractorsMutex.Unlock()
+}
+
+// ContextValue returns extractor which returns field with given name and
+// value of given context key, field is omitted if context doesn't have a
+// value for the key.
+func ContextValue(name string, key interface{}) ContextExtractor {
+	return func(ctx context.Context) Fields {
+		value := ctx.Value(key)
+		if value == nil {
+			return nil
+		}
+
+		return Fields{{name, value}}
+	}
+}
+
+// contextFields returns fields extracted from given context by all
+// registered extractors.
+func contextFields(ctx context.Context) Fields {
+	if ctx == nil {
+		return nil
+	}
+
+	contextExtractorsMutex.RLock()
+	defer contextExtractorsMutex.RUnlock()
+
+	var fields Fields
+	for _, extractor := range contextExtractors {
+		fields = fields.Merge(extractor(ctx))
+	}
+
+	return fields
+}
@@ -0,0 +1,75 @@
+package lorg
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testContextKey struct{}
+
+func TestFromContext_ReturnsLoggerOrPackageLogger(t *testing.T) {
+	test := assert.New(t)
+
+	log := NewLog()
+
+	test.Equal(log, FromContext(NewContext(context.Background(), log)))
+	test.Equal(logger, FromContext(context.Background()))
+	test.Equal(logger, FromContext(nil))
+}
+
+func TestLog_InfoContext_AttachesExtractedFields(t *testing.T) {
+	test := assert.New(t)
+
+	defer ResetContextExtractors()
+
+	AddContextExtractor(ContextValue("request", testContextKey{}))
+	AddContextExtractor(func(ctx context.Context) Fields {
+		return Fields{{"trace", "t1"}}
+	})
+
+	var buffer bytes.Buffer
+
+	log := NewLog()
+	log.SetOutput(&buffer)
+	log.SetFormat(NewFormat(`${level} ${line} %s${fields}`))
+
+	ctx := context.WithValue(context.Background(), testContextKey{}, "r1")
+
+	_, _, line, _ := runtime.Caller(0)
+	log.With("user", "alice").InfoContext(ctx, "request", "trace", "t2")
+	log.DebugContext(ctx, "skipped")
+	log.WarningContext(context.Background(), "no request")
+
+	test.Equal(
+		"INFO "+strconv.Itoa(line+1)+" request user=alice request=r1 "+
+			"trace=t2\n"+
+			"WARNING "+strconv.Itoa(line+3)+" no request trace=t1\n",
+		buffer.String(),
+	)
+}
+
+func TestSlogHandler_Handle_AttachesExtractedFields(t *testing.T) {
+	test := assert.New(t)
+
+	defer ResetContextExtractors()
+
+	AddContextExtractor(ContextValue("request", testContextKey{}))
+
+	var buffer bytes.Buffer
+
+	log := NewLog()
+	log.SetOutput(&buffer)
+	log.SetFormat(NewFormat(`%s${fields}`))
+
+	ctx := context.WithValue(context.Background(), testContextKey{}, "r1")
+
+	slog.New(NewSlogHandler(log)).InfoContext(ctx, "done", "took", 2)
+
+	test.Equal("done request=r1 took=2\n", buffer.String())
+}
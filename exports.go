@@ -1,6 +1,7 @@
 package lorg
 
 import (
+	"context"
 	"io"
 )
 
@@ -18,6 +19,19 @@ func SetLevel(level Level) {
 	logger.SetLevel(level)
 }
 
+// SetLevelFromEnv sets the logging level which is specified by environment
+// variable with given name, see ParseLevel for accepted values. Level is not
+// changed if variable is not set or empty.
+func SetLevelFromEnv(name string) error {
+	return logger.SetLevelFromEnv(name)
+}
+
+// HandleLevelSignals changes logging level of package logger after receiving
+// SIGUSR1 (cycle) and SIGUSR2 (toggle) signals, see Log.HandleLevelSignals.
+func HandleLevelSignals(toggle Level) func() {
+	return logger.HandleLevelSignals(toggle)
+}
+
 // GetLevel returns the logging level for the given logger.
 func GetLevel() Level {
 	return logger.GetLevel()
@@ -43,11 +57,31 @@ func SetOutput(output io.Writer) {
 	logger.SetOutput(output)
 }
 
+// SetStackTraceLevel forces package logger to capture stack of the caller
+// for records with given level or more severe levels, see
+// Log.SetStackTraceLevel.
+func SetStackTraceLevel(level Level) {
+	logger.SetStackTraceLevel(level)
+}
+
+// AddHook adds hook which will be fired for records of package logger, see
+// Log.AddHook.
+func AddHook(hook Hook) {
+	logger.AddHook(hook)
+}
+
+// SetErrorHandler sets function which will be called with errors of hooks,
+// outputs and flushing of package logger instead of printing them to stderr.
+func SetErrorHandler(handler func(error)) {
+	logger.SetErrorHandler(handler)
+}
+
 // Fatal logs record if given logger level is equal or above LevelFatal, and
 // calls os.Exit(1) after logging.
 // Arguments are handled in the manner of fmt.Print.
 func Fatal(value ...interface{}) {
 	logger.log(LevelFatal, value...)
+	logger.flush()
 	Exiter(1)
 }
 
@@ -56,6 +90,7 @@ func Fatal(value ...interface{}) {
 // Arguments are handled in the manner of fmt.Print.
 func Fatalf(format string, value ...interface{}) {
 	logger.logf(LevelFatal, format, value...)
+	logger.flush()
 	Exiter(1)
 }
 
@@ -129,13 +164,116 @@ func Tracef(format string, value ...interface{}) {
 	logger.logf(LevelTrace, format, value...)
 }
 
+// Fatalw logs record with given message and fields if given logger level is
+// equal or above LevelFatal, and calls os.Exit(1) after logging.
+// Fields are passed as alternating keys and values like in With.
+func Fatalw(message string, keyvalues ...interface{}) {
+	logger.logw(LevelFatal, message, keyvalues...)
+	logger.flush()
+	Exiter(1)
+}
+
+// Errorw logs record with given message and fields if given logger level is
+// equal or above LevelError.
+// Fields are passed as alternating keys and values like in With.
+func Errorw(message string, keyvalues ...interface{}) {
+	logger.logw(LevelError, message, keyvalues...)
+}
+
+// Warningw logs record with given message and fields if given logger level
+// is equal or above LevelWarning.
+// Fields are passed as alternating keys and values like in With.
+func Warningw(message string, keyvalues ...interface{}) {
+	logger.logw(LevelWarning, message, keyvalues...)
+}
+
+// Infow logs record with given message and fields if given logger level is
+// equal or above LevelInfo.
+// Fields are passed as alternating keys and values like in With.
+func Infow(message string, keyvalues ...interface{}) {
+	logger.logw(LevelInfo, message, keyvalues...)
+}
+
+// Debugw logs record with given message and fields if given logger level is
+// equal or above LevelDebug.
+// Fields are passed as alternating keys and values like in With.
+func Debugw(message string, keyvalues ...interface{}) {
+	logger.logw(LevelDebug, message, keyvalues...)
+}
+
+// Tracew logs record with given message and fields if given logger level is
+// equal or above LevelTrace.
+// Fields are passed as alternating keys and values like in With.
+func Tracew(message string, keyvalues ...interface{}) {
+	logger.logw(LevelTrace, message, keyvalues...)
+}
+
+// FatalContext logs record with given message, fields extracted from given
+// context and given fields if given logger level is equal or above LevelFatal,
+// and calls os.Exit(1) after logging.
+// Fields are passed as alternating keys and values like in With.
+func FatalContext(
+	ctx context.Context, message string, keyvalues ...interface{},
+) {
+	logger.logContext(ctx, LevelFatal, message, keyvalues...)
+	logger.flush()
+	Exiter(1)
+}
+
+// ErrorContext logs record with given message, fields extracted from given
+// context and given fields if given logger level is equal or above LevelError.
+// Fields are passed as alternating keys and values like in With.
+func ErrorContext(
+	ctx context.Context, message string, keyvalues ...interface{},
+) {
+	logger.logContext(ctx, LevelError, message, keyvalues...)
+}
+
+// WarningContext logs record with given message, fields extracted from
+// given context and given fields if given logger level is equal or above
+// LevelWarning.
+// Fields are passed as alternating keys and values like in With.
+func WarningContext(
+	ctx context.Context, message string, keyvalues ...interface{},
+) {
+	logger.logContext(ctx, LevelWarning, message, keyvalues...)
+}
+
+// InfoContext logs record with given message, fields extracted from given
+// context and given fields if given logger level is equal or above LevelInfo.
+// Fields are passed as alternating keys and values like in With.
+func InfoContext(
+	ctx context.Context, message string, keyvalues ...interface{},
+) {
+	logger.logContext(ctx, LevelInfo, message, keyvalues...)
+}
+
+// DebugContext logs record with given message, fields extracted from given
+// context and given fields if given logger level is equal or above LevelDebug.
+// Fields are passed as alternating keys and values like in With.
+func DebugContext(
+	ctx context.Context, message string, keyvalues ...interface{},
+) {
+	logger.logContext(ctx, LevelDebug, message, keyvalues...)
+}
+
+// TraceContext logs record with given message, fields extracted from given
+// context and given fields if given logger level is equal or above LevelTrace.
+// Fields are passed as alternating keys and values like in With.
+func TraceContext(
+	ctx context.Context, message string, keyvalues ...interface{},
+) {
+	logger.logContext(ctx, LevelTrace, message, keyvalues...)
+}
+
 // SetPrefix of given logger, prefix placeholder should be used in logger
 // format.
 func SetPrefix(prefix string) {
 	logger.SetPrefix(prefix)
 }
 
-// NewChild of given logger, child inherit level, format and output options.
+// NewChild of given logger, child inherit level, format, output and exiter
+// options.
 func NewChild() *Log {
 	return logger.NewChild()
 }
@@ -146,6 +284,34 @@ func NewChildWithPrefix(prefix string) *Log {
 	return logger.NewChildWithPrefix(prefix)
 }
 
+// Named returns named child of package logger, see Log.Named.
+func Named(name string) *Log {
+	return logger.Named(name)
+}
+
+// SetLevelSpec sets levels of named children of package logger using given
+// spec, see Log.SetLevelSpec.
+func SetLevelSpec(spec string) error {
+	return logger.SetLevelSpec(spec)
+}
+
+// SetLevelSpecFromEnv sets levels of named children of package logger using
+// spec which is specified by environment variable with given name.
+func SetLevelSpecFromEnv(name string) error {
+	return logger.SetLevelSpecFromEnv(name)
+}
+
+// With returns a child of package logger which attaches given fields to every
+// log record. Arguments are alternating keys and values.
+func With(keyvalues ...interface{}) *Log {
+	return logger.With(keyvalues...)
+}
+
+// WithFields is the same as With, but takes already built Fields.
+func WithFields(fields Fields) *Log {
+	return logger.WithFields(fields)
+}
+
 // SetIndentLines changes Log's option that responsible for indenting log entry
 // lines in one format.
 // With this option log entries with newline symbols will be indented like as
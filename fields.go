@@ -0,0 +1,171 @@
+package lorg
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const (
+	// FieldBadKey is the key which will be used for value which has been
+	// passed without pair to With or *w logging functions.
+	FieldBadKey = "!BADKEY"
+)
+
+// Field is a key/value pair which can be attached to log records using
+// Log.With or Log.Infow-like functions.
+//
+// Fields are rendered by ${fields} placeholder.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Fields is an ordered list of fields attached to log record.
+type Fields []Field
+
+// NewFields creates Fields from alternating keys and values like as following:
+//
+//	NewFields("user", id, "request", requestID)
+//
+// Keys which are not strings will be converted using fmt.Sprint, value
+// without pair will be stored with FieldBadKey key.
+func NewFields(keyvalues ...interface{}) Fields {
+	fields := make(Fields, 0, (len(keyvalues)+1)/2)
+
+	for index := 0; index < len(keyvalues); index += 2 {
+		if index+1 == len(keyvalues) {
+			fields = append(fields, Field{FieldBadKey, keyvalues[index]})
+			break
+		}
+
+		key, ok := keyvalues[index].(string)
+		if !ok {
+			key = fmt.Sprint(keyvalues[index])
+		}
+
+		fields = append(fields, Field{key, keyvalues[index+1]})
+	}
+
+	return fields
+}
+
+// Merge returns new Fields which contains given fields and other fields,
+// values of other fields override values of given fields with same keys.
+//
+// Neither given fields nor other fields are modified.
+func (fields Fields) Merge(other Fields) Fields {
+	if len(other) == 0 {
+		return fields
+	}
+
+	if len(fields) == 0 {
+		return other
+	}
+
+	merged := make(Fields, len(fields), len(fields)+len(other))
+	copy(merged, fields)
+
+	for _, field := range other {
+		found := false
+		for index := range merged {
+			if merged[index].Key == field.Key {
+				merged[index].Value = field.Value
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			merged = append(merged, field)
+		}
+	}
+
+	return merged
+}
+
+// String returns fields in the key=value form separated by spaces, values
+// which contain spaces, quotes or equal signs are quoted.
+func (fields Fields) String() string {
+	if len(fields) == 0 {
+		return ""
+	}
+
+	return string(appendFields(nil, fields)[1:])
+}
+
+// appendFields appends fields in the key=value form prepended by space to
+// given buffer.
+func appendFields(buffer []byte, fields Fields) []byte {
+	for _, field := range fields {
+		buffer = append(buffer, ' ')
+		buffer = append(buffer, field.Key...)
+		buffer = append(buffer, '=')
+		buffer = appendFieldValue(buffer, field.Value)
+	}
+
+	return buffer
+}
+
+// appendFieldValue appends value formatted like formatFieldValue does, but
+// without allocations for strings, integers and booleans.
+func appendFieldValue(buffer []byte, value interface{}) []byte {
+	switch value := value.(type) {
+	case string:
+		if needsQuoting(value) {
+			return strconv.AppendQuote(buffer, value)
+		}
+
+		return append(buffer, value...)
+	case int:
+		return strconv.AppendInt(buffer, int64(value), 10)
+	case int64:
+		return strconv.AppendInt(buffer, value, 10)
+	case int32:
+		return strconv.AppendInt(buffer, int64(value), 10)
+	case uint:
+		return strconv.AppendUint(buffer, uint64(value), 10)
+	case uint64:
+		return strconv.AppendUint(buffer, value, 10)
+	case uint32:
+		return strconv.AppendUint(buffer, uint64(value), 10)
+	case bool:
+		return strconv.AppendBool(buffer, value)
+	}
+
+	return append(buffer, formatFieldValue(value)...)
+}
+
+func formatFieldValue(value interface{}) string {
+	var text string
+	switch value := value.(type) {
+	case string:
+		text = value
+	default:
+		text = fmt.Sprint(value)
+	}
+
+	if needsQuoting(text) {
+		return strconv.Quote(text)
+	}
+
+	return text
+}
+
+func needsQuoting(text string) bool {
+	if text == "" {
+		return true
+	}
+
+	for _, symbol := range text {
+		if symbol <= ' ' || symbol == '=' || symbol == '"' ||
+			symbol == 0x7f {
+			return true
+		}
+	}
+
+	return false
+}
+
+func getFields(fields Fields) string {
+	return string(appendFields(nil, fields))
+}
@@ -0,0 +1,37 @@
+package lorg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFields_ReturnsFieldsFromKeyValues(t *testing.T) {
+	test := assert.New(t)
+
+	test.Equal(
+		Fields{{"user", "root"}, {"1", 2}, {FieldBadKey, "lonely"}},
+		NewFields("user", "root", 1, 2, "lonely"),
+	)
+}
+
+func TestFields_Merge_OverridesSameKeys(t *testing.T) {
+	test := assert.New(t)
+
+	parent := NewFields("a", 1, "b", 2)
+	child := parent.Merge(NewFields("b", 3, "c", 4))
+
+	test.Equal(Fields{{"a", 1}, {"b", 3}, {"c", 4}}, child)
+	test.Equal(Fields{{"a", 1}, {"b", 2}}, parent)
+}
+
+func TestFields_String_QuotesValues(t *testing.T) {
+	test := assert.New(t)
+
+	test.Equal(
+		`a=1 b="x y" c="k=v" d="\"q\"" e="" f="1\n2"`,
+		NewFields(
+			"a", 1, "b", "x y", "c", "k=v", "d", `"q"`, "e", "", "f", "1\n2",
+		).String(),
+	)
+}
@@ -3,44 +3,106 @@ package lorg
 import (
 	"strings"
 	"sync"
+	"time"
 )
 
 // Format is the actual Formatter which used by Log structure for formatting
 // log records before writing log records into Log.output.
 //
+// Besides placeholders, there are two special placeholders: ${prefix} is
+// replaced with prefix of the logger, and ${fields} is replaced by Log with
+// structured fields of the record in the key=value form prepended by space,
+// so ${fields} should be placed right after the message:
+// `${level} %s${fields}`.
+//
+// Format is compiled into a list of text and placeholder segments on first
+// use, records are rendered by appending segments into a buffer, so
+// formatting is not parsed for every record.
+//
 // Do not instantiate Format instance without using NewFormat.
 type Format struct {
 	formatting       string
 	compiled         bool
-	compileMutex     *sync.Mutex
 	replacements     []replacement
+	segments         []segment
 	placeholders     map[string]Placeholder
 	placeholderMutex *sync.RWMutex
+
+	recordPlaceholders map[string]RecordPlaceholder
 }
 
 type replacement struct {
-	value            string
-	placeholder      Placeholder
-	placeholderValue string
+	name              string
+	value             string
+	placeholder       Placeholder
+	recordPlaceholder RecordPlaceholder
+	placeholderValue  string
+
+	// appender is used instead of record placeholder if it's known how to
+	// append result of the record placeholder without allocations.
+	appender recordAppender
+
+	// static contains results of placeholder for every level without and
+	// with colors, it's filled only for placeholders which results depend
+	// only on level and placeholder value.
+	static *[2][LevelTrace + 1]string
+}
+
+type segmentKind int
+
+const (
+	segmentText segmentKind = iota
+	segmentReplacement
+	segmentPrefix
+	segmentMessage
+	segmentFields
+)
+
+// segment is a part of compiled format, text is used by text segments and
+// replacement is the index of replacement for placeholder segments.
+type segment struct {
+	kind        segmentKind
+	text        string
+	replacement int
+}
+
+// renderOptions describe how Format renders records for Log.
+type renderOptions struct {
+	// colors enables color placeholders.
+	colors bool
+
+	// template forces Format to keep `%s` and `${fields}` as is, like
+	// Render does.
+	template bool
+
+	// shift and indentLines are the same as Log options with the same
+	// names.
+	shift       int
+	indentLines bool
 }
 
-// NewFormat creates Format instance with specified formatting and default
-// placeholders: level (PlaceholderLevel), date (PlaceholderDate), line
-// (PlaceholderLine) and file (PlaceholderFile).
+// NewFormat creates Format instance with specified formatting, default
+// placeholders: level (PlaceholderLevel), color (PlaceholderColor), reset
+// (PlaceholderReset) and colorlevel (PlaceholderColorLevel), and default
+// record placeholders: time (RecordPlaceholderTime), line
+// (RecordPlaceholderLine), file (RecordPlaceholderFile), func
+// (RecordPlaceholderFunc), package (RecordPlaceholderPackage) and stack
+// (RecordPlaceholderStack).
 //
-// Format placeholders can be changed or added using SetPlaceholders or
-// SetPlaceholder methods.
+// Format placeholders can be changed or added using SetPlaceholders,
+// SetPlaceholder, SetRecordPlaceholders or SetRecordPlaceholder methods.
 func NewFormat(formatting string) *Format {
 	format := &Format{
-		formatting:       formatting,
-		placeholderMutex: &sync.RWMutex{},
-		compileMutex:     &sync.Mutex{},
+		formatting:         formatting,
+		placeholderMutex:   &sync.RWMutex{},
+		recordPlaceholders: map[string]RecordPlaceholder{},
 	}
 
 	// we are should not assing format.placeholders to defaultPlaceholders
 	// because maps in go passes by reference.
 
 	format.SetPlaceholders(DefaultPlaceholders)
+	format.SetRecordPlaceholders(DefaultRecordPlaceholders)
 
 	return format
 }
@@ -51,10 +113,47 @@ func (format *Format) SetPlaceholder(name string, placeholder Placeholder) {
 	format.Reset()
 
 	format.placeholderMutex.Lock()
+	delete(format.recordPlaceholders, name)
 	format.placeholders[name] = placeholder
 	format.placeholderMutex.Unlock()
 }
 
+// SetRecordPlaceholder sets specified record placeholder with specified
+// placeholder name for given format.
+func (format *Format) SetRecordPlaceholder(
+	name string, placeholder RecordPlaceholder,
+) {
+	format.Reset()
+
+	format.placeholderMutex.Lock()
+	delete(format.placeholders, name)
+	format.recordPlaceholders[name] = placeholder
+	format.placeholderMutex.Unlock()
+}
+
+// SetRecordPlaceholders sets specified record placeholders for given format,
+// placeholders with the same names are kept, but record placeholders are
+// preferred.
+func (format *Format) SetRecordPlaceholders(
+	placeholders map[string]RecordPlaceholder,
+) {
+	format.Reset()
+
+	format.placeholderMutex.Lock()
+
+	format.recordPlaceholders = map[string]RecordPlaceholder{}
+	for placeholderName, placeholder := range placeholders {
+		format.recordPlaceholders[placeholderName] = placeholder
+	}
+
+	format.placeholderMutex.Unlock()
+}
+
+// GetRecordPlaceholders returns record placeholders of given format.
+func (format *Format) GetRecordPlaceholders() map[string]RecordPlaceholder {
+	return format.recordPlaceholders
+}
+
 // SetPlaceholders sets specified placeholders for given format.
 func (format *Format) SetPlaceholders(placeholders map[string]Placeholder) {
 	format.Reset()
@@ -63,6 +162,7 @@ func (format *Format) SetPlaceholders(placeholders map[string]Placeholder) {
 
 	format.placeholders = map[string]Placeholder{}
 	for placeholderName, placeholder := range placeholders {
+		delete(format.recordPlaceholders, placeholderName)
 		format.placeholders[placeholderName] = placeholder
 	}
 
@@ -79,82 +179,301 @@ func (format *Format) Reset() {
 	format.placeholderMutex.Lock()
 
 	format.replacements = []replacement{}
+	format.segments = nil
 	format.compiled = false
 	cache.reset()
 
 	format.placeholderMutex.Unlock()
 }
 
+// SetPalette sets palette of levels colors which will be used by `${color}`
+// and `${colorlevel}` placeholders.
+func (format *Format) SetPalette(palette Palette) {
+	format.SetPlaceholder("color", NewPlaceholderColor(palette))
+	format.SetPlaceholder("colorlevel", NewPlaceholderColorLevel(palette))
+}
+
 // Render generates string which will be used by Log instance.
 // Here is logLevel property just for a placeholders which want to show
 // logging level, logLevel will be passed to all ran placeholders.
+//
+// Render always renders colors, Log renders colors only if output is a
+// terminal, see PlaceholderColor.
+//
+// Record placeholders receive record with given level, prefix and current
+// time, but without caller.
 func (format *Format) Render(logLevel Level, prefix string) string {
-	format.compileMutex.Lock()
-	if !format.compiled {
-		format.compile()
-	}
-	format.compileMutex.Unlock()
+	return string(format.render(
+		nil,
+		&Record{Level: logLevel, Prefix: prefix, Time: time.Now()},
+		renderOptions{colors: true, template: true},
+	))
+}
 
+// render is the same as Render, but it appends rendered record to given
+// buffer, passes given record to record placeholders and replaces `%s` and
+// `${fields}` with message and fields of the record unless template option is
+// set. render is called by Log directly instead of Render, because
+// placeholders use fixed stack depth for getting information about caller.
+func (format *Format) render(
+	buffer []byte, record *Record, options renderOptions,
+) []byte {
 	format.placeholderMutex.RLock()
-	rendered := format.formatting
-	for _, replacement := range format.replacements {
-		var placeholderValue string
+	for !format.compiled {
+		format.placeholderMutex.RUnlock()
+		format.compile()
+		format.placeholderMutex.RLock()
+	}
 
-		placeholderValue = replacement.placeholder(
-			logLevel,
-			replacement.placeholderValue,
-		)
+	colors := 0
+	if options.colors {
+		colors = 1
+	}
 
-		rendered = strings.Replace(
-			rendered, replacement.value, placeholderValue, 1,
-		)
+	start := len(buffer)
+
+	for _, segment := range format.segments {
+		switch segment.kind {
+		case segmentText:
+			buffer = append(buffer, segment.text...)
+
+		case segmentPrefix:
+			if record.Prefix != "" {
+				buffer = append(buffer, record.Prefix...)
+				buffer = append(buffer, ' ')
+			}
+
+		case segmentMessage:
+			if options.template {
+				buffer = append(buffer, "%s"...)
+				break
+			}
+
+			shift := options.shift
+			if shift == 0 && options.indentLines {
+				shift = len(buffer) - start
+			}
+
+			buffer = appendIndented(buffer, record.Message, shift)
+
+		case segmentFields:
+			if options.template {
+				buffer = append(buffer, "${fields}"...)
+				break
+			}
+
+			buffer = appendFields(buffer, record.Fields)
+
+		case segmentReplacement:
+			replacement := &format.replacements[segment.replacement]
+
+			switch {
+			case replacement.static != nil &&
+				record.Level >= LevelFatal && record.Level <= LevelTrace:
+				buffer = append(
+					buffer, replacement.static[colors][record.Level]...,
+				)
+
+			case replacement.appender != nil:
+				buffer = replacement.appender(
+					buffer, record, replacement.placeholderValue,
+				)
+
+			case replacement.recordPlaceholder != nil:
+				buffer = append(buffer, replacement.recordPlaceholder(
+					record, replacement.placeholderValue,
+				)...)
+
+			default:
+				placeholder := replacement.placeholder
+				if !options.colors {
+					colorless, ok := colorlessPlaceholders[replacement.name]
+					if ok {
+						placeholder = colorless
+					}
+				}
+
+				buffer = append(buffer, placeholder(
+					record.Level, replacement.placeholderValue,
+				)...)
+			}
+		}
 	}
 	format.placeholderMutex.RUnlock()
 
-	rendered = strings.Replace(rendered, `${prefix}`, getPrefix(prefix), 1)
-
-	return rendered
+	return buffer
 }
 
 func (format *Format) compile() {
-	// reset compiled replacements
-	format.Reset()
+	format.placeholderMutex.Lock()
+	defer format.placeholderMutex.Unlock()
+
+	if format.compiled {
+		return
+	}
 
-	var placeholder Placeholder
-	var ok bool
+	var (
+		replacements = []replacement{}
+		segments     []segment
 
-	format.placeholderMutex.RLock()
-	matches := rePlaceholder.FindAllStringSubmatch(format.formatting, -1)
+		// text which is not rendered yet, it includes unknown
+		// placeholders
+		text string
+
+		prefix  bool
+		message bool
+		fields  [2]bool
+	)
+
+	addText := func(value string) {
+		if value == "" {
+			return
+		}
+
+		last := len(segments) - 1
+		if last >= 0 && segments[last].kind == segmentText {
+			segments[last].text += value
+			return
+		}
+
+		segments = append(segments, segment{kind: segmentText, text: value})
+	}
+
+	// flush splits pending text by the first `%s`, `%s` can't be in
+	// placeholders, but it can be in unknown placeholders
+	flush := func() {
+		if !message {
+			if index := strings.Index(text, "%s"); index >= 0 {
+				addText(text[:index])
+				segments = append(segments, segment{kind: segmentMessage})
+				message = true
+				text = text[index+2:]
+			}
+		}
+
+		addText(text)
+		text = ""
+	}
+
+	last := 0
+	matches := rePlaceholder.FindAllStringSubmatchIndex(format.formatting, -1)
 	for _, match := range matches {
 		var (
-			replacementValue = match[0]
-			placeholderName  = match[1]
-			placeholderValue = match[3]
+			replacementValue = format.formatting[match[0]:match[1]]
+			placeholderName  = format.formatting[match[2]:match[3]]
+			placeholderValue string
 		)
 
-		placeholder, ok = format.placeholders[placeholderName]
-		if !ok {
-			// placeholder with specified name not found
+		if match[6] >= 0 {
+			placeholderValue = format.formatting[match[6]:match[7]]
+		}
+
+		text += format.formatting[last:match[0]]
+		last = match[1]
+
+		recordPlaceholder := format.recordPlaceholders[placeholderName]
+
+		placeholder, ok := format.placeholders[placeholderName]
+		if !ok && recordPlaceholder == nil {
+			switch {
+			case placeholderName == "prefix" && !prefix:
+				flush()
+				segments = append(segments, segment{kind: segmentPrefix})
+				prefix = true
+
+			case placeholderName == "fields" && !fields[boolIndex(message)]:
+				flush()
+				fields[boolIndex(message)] = true
+				segments = append(segments, segment{kind: segmentFields})
+
+			default:
+				// placeholder with specified name not found
+				text += replacementValue
+			}
+
 			continue
 		}
 
 		newReplacement := replacement{
-			value:            replacementValue,
-			placeholder:      placeholder,
-			placeholderValue: placeholderValue,
+			name:              placeholderName,
+			value:             replacementValue,
+			placeholder:       placeholder,
+			recordPlaceholder: recordPlaceholder,
+			placeholderValue:  placeholderValue,
+		}
+
+		if recordPlaceholder != nil {
+			newReplacement.appender = recordAppenders[funcPointer(
+				recordPlaceholder,
+			)]
+		} else if staticPlaceholders[funcPointer(placeholder)] {
+			newReplacement.static = renderStatic(
+				placeholderName, placeholder, placeholderValue,
+			)
 		}
 
-		format.replacements = append(format.replacements, newReplacement)
+		flush()
+		segments = append(segments, segment{
+			kind:        segmentReplacement,
+			replacement: len(replacements),
+		})
+
+		replacements = append(replacements, newReplacement)
 	}
-	format.placeholderMutex.RUnlock()
 
+	text += format.formatting[last:]
+	flush()
+
+	format.replacements = replacements
+	format.segments = segments
 	format.compiled = true
 }
 
-func getPrefix(prefix string) string {
-	if prefix != "" {
-		return prefix + " "
+// renderStatic renders placeholder which result depends only on level and
+// placeholder value for every level without and with colors.
+func renderStatic(
+	name string, placeholder Placeholder, value string,
+) *[2][LevelTrace + 1]string {
+	colorless, ok := colorlessPlaceholders[name]
+	if !ok {
+		colorless = placeholder
+	}
+
+	static := &[2][LevelTrace + 1]string{}
+	for level := LevelFatal; level <= LevelTrace; level++ {
+		static[0][level] = colorless(level, value)
+		static[1][level] = placeholder(level, value)
+	}
+
+	return static
+}
+
+// appendIndented appends given text to buffer, lines of text except the
+// first one are indented by given amount of spaces.
+func appendIndented(buffer []byte, text string, shift int) []byte {
+	if shift <= 0 {
+		return append(buffer, text...)
+	}
+
+	for {
+		index := strings.IndexByte(text, '\n')
+		if index < 0 {
+			return append(buffer, text...)
+		}
+
+		buffer = append(buffer, text[:index+1]...)
+		for i := 0; i < shift; i++ {
+			buffer = append(buffer, ' ')
+		}
+
+		text = text[index+1:]
+	}
+}
+
+func boolIndex(value bool) int {
+	if value {
+		return 1
 	}
 
-	return ""
+	return 0
 }
@@ -0,0 +1,165 @@
+package lorg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	// JSONFormatDefaultTimeLayout is the time layout which will be used by
+	// JSONFormat for the time key if layout is not specified.
+	JSONFormatDefaultTimeLayout = time.RFC3339Nano
+)
+
+// ensure that JSONFormat implements RecordFormatter interface.
+var _ RecordFormatter = (*JSONFormat)(nil)
+
+// JSONFormat is the Formatter which renders every log record as a single
+// line JSON object like as following:
+//
+//	{"time":"...","level":"INFO","prefix":"db","file":"a.go","line":12,
+//	 "message":"text","fields":{"user":"alice"},"stack":["main.main a.go:12"]}
+//
+// time, prefix, file, line, fields and stack keys are omitted if they are
+// empty.
+//
+// Placeholders of JSONFormat are rendered as additional string keys of the
+// object, placeholders are called with empty value. By default JSONFormat
+// has no placeholders.
+//
+// Do not instantiate JSONFormat instance without using NewJSONFormat.
+type JSONFormat struct {
+	*placeholderSet
+
+	timeLayout string
+	fileMode   string
+}
+
+// NewJSONFormat creates JSONFormat instance with default time layout
+// (JSONFormatDefaultTimeLayout) and short file mode.
+func NewJSONFormat() *JSONFormat {
+	return &JSONFormat{
+		placeholderSet: newPlaceholderSet(),
+		timeLayout:     JSONFormatDefaultTimeLayout,
+		fileMode:       "short",
+	}
+}
+
+// SetTimeLayout sets layout which will be used for formatting time key,
+// layout "timestamp" forces JSONFormat to render unix timestamp as number.
+func (format *JSONFormat) SetTimeLayout(layout string) *JSONFormat {
+	format.timeLayout = layout
+	return format
+}
+
+// SetFileMode sets mode of file key, mode can be "short" or "long" like in
+// PlaceholderFile.
+func (format *JSONFormat) SetFileMode(mode string) *JSONFormat {
+	format.fileMode = mode
+	return format
+}
+
+// Reset does nothing, JSONFormat has no state.
+func (format *JSONFormat) Reset() {}
+
+// Render returns template which contains only message, Log uses RenderRecord
+// instead of Render for JSONFormat.
+func (format *JSONFormat) Render(logLevel Level, prefix string) string {
+	return "%s"
+}
+
+// RenderRecord returns JSON object for given record.
+func (format *JSONFormat) RenderRecord(record *Record) string {
+	buffer := &bytes.Buffer{}
+	buffer.WriteByte('{')
+
+	switch {
+	case record.Time.IsZero():
+		// record without time, for example, slog record
+
+	case format.timeLayout == "timestamp":
+		writeJSONKey(buffer, "time", record.Time.Unix())
+
+	default:
+		writeJSONKey(buffer, "time", record.Time.Format(format.timeLayout))
+	}
+
+	writeJSONKey(buffer, "level", record.Level.String())
+
+	if record.Prefix != "" {
+		writeJSONKey(buffer, "prefix", record.Prefix)
+	}
+
+	if record.File != "" {
+		writeJSONKey(buffer, "file", formatFile(record.File, format.fileMode))
+		writeJSONKey(buffer, "line", record.Line)
+	}
+
+	writeJSONKey(buffer, "message", record.Message)
+
+	format.renderPlaceholders(record, func(name, value string) {
+		writeJSONKey(buffer, name, value)
+	})
+
+	if len(record.Fields) != 0 {
+		buffer.WriteString(`,"fields":{`)
+		for index, field := range record.Fields {
+			if index > 0 {
+				buffer.WriteByte(',')
+			}
+
+			writeJSON(buffer, field.Key)
+			buffer.WriteByte(':')
+			writeJSON(buffer, jsonFieldValue(field.Value))
+		}
+		buffer.WriteByte('}')
+	}
+
+	if len(record.Stack) != 0 {
+		writeJSONKey(buffer, "stack", formatStack(record.Stack, format.fileMode))
+	}
+
+	buffer.WriteByte('}')
+
+	return buffer.String()
+}
+
+func writeJSONKey(buffer *bytes.Buffer, key string, value interface{}) {
+	if buffer.Len() > 1 {
+		buffer.WriteByte(',')
+	}
+
+	writeJSON(buffer, key)
+	buffer.WriteByte(':')
+	writeJSON(buffer, value)
+}
+
+func writeJSON(buffer *bytes.Buffer, value interface{}) {
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+
+	err := encoder.Encode(value)
+	if err != nil {
+		// value can't be encoded, so encode it's string representation,
+		// which can't fail.
+		_ = encoder.Encode(fmt.Sprint(value))
+	}
+
+	// json.Encoder always terminates value with newline
+	buffer.Truncate(buffer.Len() - 1)
+}
+
+func jsonFieldValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case error:
+		return value.Error()
+	case json.Marshaler:
+		return value
+	case fmt.Stringer:
+		return value.String()
+	}
+
+	return value
+}
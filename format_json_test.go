@@ -0,0 +1,71 @@
+package lorg
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONFormat_ImplementsRecordFormatterInterface(t *testing.T) {
+	test := assert.New(t)
+
+	test.Implements((*RecordFormatter)(nil), &JSONFormat{})
+}
+
+func TestJSONFormat_RenderRecord_EscapesMessage(t *testing.T) {
+	test := assert.New(t)
+
+	var buffer bytes.Buffer
+
+	log := NewLog()
+	log.SetOutput(&buffer)
+	log.SetFormat(NewJSONFormat().SetTimeLayout("timestamp"))
+	log.SetPrefix("db")
+
+	_, file, line, _ := runtime.Caller(0)
+	log.With("user", "alice").Infow(
+		"say \"hello\"\nworld <3", "err", errors.New("oops"),
+	)
+
+	test.Equal(1, bytes.Count(buffer.Bytes(), []byte("\n")))
+
+	var record map[string]interface{}
+	test.NoError(json.Unmarshal(buffer.Bytes(), &record))
+
+	test.Equal("INFO", record["level"])
+	test.Equal("db", record["prefix"])
+	test.Equal(filepath.Base(file), record["file"])
+	test.EqualValues(line+1, record["line"])
+	test.Equal("say \"hello\"\nworld <3", record["message"])
+	test.Equal(
+		map[string]interface{}{"user": "alice", "err": "oops"},
+		record["fields"],
+	)
+	test.IsType(float64(0), record["time"])
+}
+
+func TestJSONFormat_RenderRecord_RendersPlaceholdersAsKeys(t *testing.T) {
+	test := assert.New(t)
+
+	format := NewJSONFormat().SetTimeLayout("timestamp")
+	format.SetPlaceholder("host", func(_ Level, _ string) string {
+		return "local"
+	})
+
+	rendered := format.RenderRecord(&Record{
+		Level:   LevelError,
+		Time:    time.Unix(1500000000, 0),
+		Message: "x",
+	})
+
+	test.Equal(
+		`{"time":1500000000,"level":"ERROR","message":"x","host":"local"}`,
+		rendered,
+	)
+}
@@ -0,0 +1,141 @@
+package lorg
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// LogfmtFormatDefaultTimeLayout is the time layout which will be used by
+	// LogfmtFormat for the ts key if layout is not specified.
+	LogfmtFormatDefaultTimeLayout = time.RFC3339
+)
+
+// ensure that LogfmtFormat implements RecordFormatter interface.
+var _ RecordFormatter = (*LogfmtFormat)(nil)
+
+// LogfmtFormat is the Formatter which renders every log record in the logfmt
+// form like as following:
+//
+//	ts=2016-01-02T09:21:44+03:00 level=info prefix=db caller=a.go:12
+//	msg="text with spaces" user=alice
+//
+// ts, prefix and caller keys are omitted if they are empty, values which
+// contain spaces, quotes or equal signs are quoted. Stack of the record is
+// rendered as the last key with frames separated by semicolons.
+//
+// Placeholders of LogfmtFormat are rendered as additional keys after msg key,
+// placeholders are called with empty value. By default LogfmtFormat has no
+// placeholders.
+//
+// Do not instantiate LogfmtFormat instance without using NewLogfmtFormat.
+type LogfmtFormat struct {
+	*placeholderSet
+
+	timeLayout string
+	fileMode   string
+}
+
+// NewLogfmtFormat creates LogfmtFormat instance with default time layout
+// (LogfmtFormatDefaultTimeLayout) and short file mode.
+func NewLogfmtFormat() *LogfmtFormat {
+	return &LogfmtFormat{
+		placeholderSet: newPlaceholderSet(),
+		timeLayout:     LogfmtFormatDefaultTimeLayout,
+		fileMode:       "short",
+	}
+}
+
+// SetTimeLayout sets layout which will be used for formatting ts key,
+// layout "timestamp" forces LogfmtFormat to render unix timestamp.
+func (format *LogfmtFormat) SetTimeLayout(layout string) *LogfmtFormat {
+	format.timeLayout = layout
+	return format
+}
+
+// SetFileMode sets mode of file in caller key, mode can be "short" or "long"
+// like in PlaceholderFile.
+func (format *LogfmtFormat) SetFileMode(mode string) *LogfmtFormat {
+	format.fileMode = mode
+	return format
+}
+
+// Reset does nothing, LogfmtFormat has no state.
+func (format *LogfmtFormat) Reset() {}
+
+// Render returns template which contains only message, Log uses RenderRecord
+// instead of Render for LogfmtFormat.
+func (format *LogfmtFormat) Render(logLevel Level, prefix string) string {
+	return "%s"
+}
+
+// RenderRecord returns logfmt line for given record.
+func (format *LogfmtFormat) RenderRecord(record *Record) string {
+	buffer := &bytes.Buffer{}
+
+	switch {
+	case record.Time.IsZero():
+		// record without time, for example, slog record
+
+	case format.timeLayout == "timestamp":
+		writeLogfmtKey(buffer, "ts", strconv.FormatInt(record.Time.Unix(), 10))
+
+	default:
+		writeLogfmtKey(buffer, "ts", record.Time.Format(format.timeLayout))
+	}
+
+	writeLogfmtKey(buffer, "level", strings.ToLower(record.Level.String()))
+
+	if record.Prefix != "" {
+		writeLogfmtKey(buffer, "prefix", record.Prefix)
+	}
+
+	if record.File != "" {
+		writeLogfmtKey(
+			buffer,
+			"caller",
+			formatFile(record.File, format.fileMode)+":"+
+				strconv.Itoa(record.Line),
+		)
+	}
+
+	writeLogfmtKey(buffer, "msg", record.Message)
+
+	format.renderPlaceholders(record, func(name, value string) {
+		writeLogfmtKey(buffer, name, value)
+	})
+
+	for _, field := range record.Fields {
+		writeLogfmtKey(buffer, field.Key, field.Value)
+	}
+
+	if len(record.Stack) != 0 {
+		writeLogfmtKey(
+			buffer,
+			"stack",
+			strings.Join(formatStack(record.Stack, format.fileMode), "; "),
+		)
+	}
+
+	return buffer.String()
+}
+
+func writeLogfmtKey(buffer *bytes.Buffer, key string, value interface{}) {
+	if buffer.Len() > 0 {
+		buffer.WriteByte(' ')
+	}
+
+	buffer.WriteString(strings.Map(logfmtKeyRune, key))
+	buffer.WriteByte('=')
+	buffer.WriteString(formatFieldValue(value))
+}
+
+func logfmtKeyRune(symbol rune) rune {
+	if symbol <= ' ' || symbol == '=' || symbol == '"' || symbol == 0x7f {
+		return '_'
+	}
+
+	return symbol
+}
@@ -0,0 +1,64 @@
+package lorg
+
+import (
+	"bytes"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogfmtFormat_ImplementsRecordFormatterInterface(t *testing.T) {
+	test := assert.New(t)
+
+	test.Implements((*RecordFormatter)(nil), &LogfmtFormat{})
+}
+
+func TestLogfmtFormat_RenderRecord_QuotesValues(t *testing.T) {
+	test := assert.New(t)
+
+	rendered := NewLogfmtFormat().RenderRecord(&Record{
+		Level:   LevelWarning,
+		Time:    time.Date(2016, 1, 2, 9, 21, 44, 0, time.UTC),
+		Prefix:  "db",
+		Message: `say "hello"`,
+		Fields:  NewFields("user", "alice", "query", "a=b", "key with space", 1),
+		File:    "/src/a.go",
+		Line:    12,
+	})
+
+	test.Equal(
+		`ts=2016-01-02T09:21:44Z level=warning prefix=db caller=a.go:12 `+
+			`msg="say \"hello\"" user=alice query="a=b" key_with_space=1`,
+		rendered,
+	)
+
+	test.Equal(
+		"level=info msg=untimed",
+		NewLogfmtFormat().RenderRecord(
+			&Record{Level: LevelInfo, Message: "untimed"},
+		),
+	)
+}
+
+func TestLogfmtFormat_UsedBySetFormat(t *testing.T) {
+	test := assert.New(t)
+
+	var buffer bytes.Buffer
+
+	log := NewLog()
+	log.SetOutput(&buffer)
+	log.SetFormat(NewLogfmtFormat().SetTimeLayout("timestamp"))
+
+	_, file, line, _ := runtime.Caller(0)
+	log.Error("failed")
+
+	test.Regexp(
+		`^ts=\d+ level=error caller=`+filepath.Base(file)+`:`+
+			strconv.Itoa(line+1)+` msg=failed\n$`,
+		buffer.String(),
+	)
+}
@@ -1,6 +1,7 @@
 package lorg
 
 import (
+	"bytes"
 	"fmt"
 	"testing"
 
@@ -379,3 +380,64 @@ func TestFormat_Reset_DesolatesReplacementAndUnsetsCompiledFlag(
 
 	test.Empty(format.replacements)
 }
+
+func TestFormat_SetRecordPlaceholder_ReplacesPlaceholderWithSameName(
+	t *testing.T,
+) {
+	test := assert.New(t)
+
+	format := NewFormat(`${level} ${record:x} %s`)
+	format.SetRecordPlaceholder(
+		"level", func(record *Record, _ string) string {
+			return "<" + record.Level.String() + ">"
+		},
+	)
+	format.SetRecordPlaceholder(
+		"record", func(record *Record, value string) string {
+			return fmt.Sprintf(
+				"%s:%s:%s:%s", value, record.Prefix, record.Message,
+				record.Fields,
+			)
+		},
+	)
+
+	test.NotContains(format.GetPlaceholders(), "level")
+	test.Contains(format.GetRecordPlaceholders(), "level")
+
+	test.Equal("<ERROR> x:app:: %s", format.Render(LevelError, "app"))
+
+	log := NewLog()
+	buffer := &bytes.Buffer{}
+	log.SetOutput(buffer)
+	log.SetFormat(format)
+
+	log.With("a", 1).Warning("message")
+
+	test.Equal("<WARNING> x::message:a=1 message\n", buffer.String())
+
+	format.SetPlaceholder("level", PlaceholderLevel)
+	test.NotContains(format.GetRecordPlaceholders(), "level")
+	test.Equal("INFO x::: %s", format.Render(LevelInfo, ""))
+}
+
+func TestFormat_Render_KeepsMessageAndFieldsForLog(t *testing.T) {
+	test := assert.New(t)
+
+	format := NewFormat(`${prefix}${level} ${x:%s} %s${fields} %s${fields}`)
+
+	test.Equal(
+		"app INFO ${x:%s} %s${fields} %s${fields}",
+		format.Render(LevelInfo, "app"),
+	)
+
+	log := NewLog()
+	buffer := &bytes.Buffer{}
+	log.SetOutput(buffer)
+	log.SetFormat(format)
+
+	log.With("a", 1).Info("message")
+
+	test.Equal(
+		"INFO ${x:message} %s a=1 %s${fields}\n", buffer.String(),
+	)
+}
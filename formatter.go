@@ -1,5 +1,10 @@
 package lorg
 
+import (
+	"sort"
+	"sync"
+)
+
 // Formatter is the interface which implemented by Format structure, it's
 // usable if you want to create your own formating mechanism.
 type Formatter interface {
@@ -23,3 +28,129 @@ type Formatter interface {
 	// Reset Formatter state.
 	Reset()
 }
+
+// RecordFormatter is the interface which can be implemented by Formatter if
+// it needs the whole log record instead of the fmt-style template, for
+// example, for escaping message text.
+//
+// If Formatter implements RecordFormatter, Log will use RenderRecord instead
+// of Render and will write returned string followed by newline. Record is
+// reused by Log after RenderRecord returns, so it should not be retained.
+type RecordFormatter interface {
+	Formatter
+
+	// RenderRecord should return string representation of the given record
+	// without trailing newline.
+	RenderRecord(record *Record) string
+}
+
+// placeholderSet implements placeholders part of Formatter interface for
+// record formatters which render placeholders as additional keys.
+type placeholderSet struct {
+	placeholders       map[string]Placeholder
+	recordPlaceholders map[string]RecordPlaceholder
+	placeholderMutex   *sync.RWMutex
+}
+
+func newPlaceholderSet() *placeholderSet {
+	return &placeholderSet{
+		placeholders:       map[string]Placeholder{},
+		recordPlaceholders: map[string]RecordPlaceholder{},
+		placeholderMutex:   &sync.RWMutex{},
+	}
+}
+
+// SetPlaceholder sets specified placeholder with specified placeholder name
+// for given format.
+func (format *placeholderSet) SetPlaceholder(
+	name string, placeholder Placeholder,
+) {
+	format.placeholderMutex.Lock()
+	delete(format.recordPlaceholders, name)
+	format.placeholders[name] = placeholder
+	format.placeholderMutex.Unlock()
+}
+
+// SetPlaceholders sets specified placeholders for given format.
+func (format *placeholderSet) SetPlaceholders(
+	placeholders map[string]Placeholder,
+) {
+	format.placeholderMutex.Lock()
+
+	format.placeholders = map[string]Placeholder{}
+	for placeholderName, placeholder := range placeholders {
+		delete(format.recordPlaceholders, placeholderName)
+		format.placeholders[placeholderName] = placeholder
+	}
+
+	format.placeholderMutex.Unlock()
+}
+
+// GetPlaceholders returns placeholders of given format.
+func (format *placeholderSet) GetPlaceholders() map[string]Placeholder {
+	return format.placeholders
+}
+
+// SetRecordPlaceholder sets specified record placeholder with specified
+// placeholder name for given format.
+func (format *placeholderSet) SetRecordPlaceholder(
+	name string, placeholder RecordPlaceholder,
+) {
+	format.placeholderMutex.Lock()
+	delete(format.placeholders, name)
+	format.recordPlaceholders[name] = placeholder
+	format.placeholderMutex.Unlock()
+}
+
+// SetRecordPlaceholders sets specified record placeholders for given format,
+// placeholders with the same names are kept, but record placeholders are
+// preferred.
+func (format *placeholderSet) SetRecordPlaceholders(
+	placeholders map[string]RecordPlaceholder,
+) {
+	format.placeholderMutex.Lock()
+
+	format.recordPlaceholders = map[string]RecordPlaceholder{}
+	for placeholderName, placeholder := range placeholders {
+		format.recordPlaceholders[placeholderName] = placeholder
+	}
+
+	format.placeholderMutex.Unlock()
+}
+
+// GetRecordPlaceholders returns record placeholders of given format.
+func (format *placeholderSet) GetRecordPlaceholders() map[string]RecordPlaceholder {
+	return format.recordPlaceholders
+}
+
+// renderPlaceholders calls all placeholders in order of their names and
+// passes results to given callback.
+func (format *placeholderSet) renderPlaceholders(
+	record *Record, callback func(name, value string),
+) {
+	format.placeholderMutex.RLock()
+
+	names := make(
+		[]string, 0,
+		len(format.placeholders)+len(format.recordPlaceholders),
+	)
+	for name := range format.placeholders {
+		names = append(names, name)
+	}
+
+	for name := range format.recordPlaceholders {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if placeholder, ok := format.recordPlaceholders[name]; ok {
+			callback(name, placeholder(record, ""))
+		} else {
+			callback(name, format.placeholders[name](record.Level, ""))
+		}
+	}
+
+	format.placeholderMutex.RUnlock()
+}
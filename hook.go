@@ -0,0 +1,135 @@
+package lorg
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+var (
+	// ErrDropRecord can be returned by Hook.Fire for dropping the record,
+	// dropped record is not written and the error is not passed to error
+	// handler of the logger.
+	ErrDropRecord = errors.New("record is dropped by hook")
+
+	// AllLevels contains all logging levels, it can be returned by
+	// Hook.Levels if hook should be fired for every record.
+	AllLevels = []Level{
+		LevelFatal, LevelError, LevelWarning, LevelInfo, LevelDebug,
+		LevelTrace,
+	}
+)
+
+// Hook is the interface of handlers which are fired by Log for every record
+// of levels returned by Levels, for example, for counting errors or sending
+// them to an incident tracker.
+//
+// Fire is called before the record is rendered and written, so hook can
+// change message or fields of the record or drop the record by returning
+// ErrDropRecord. Fields should be replaced instead of changing them in
+// place, because they are shared with the logger:
+//
+//	record.Fields = record.Fields.Merge(lorg.NewFields("host", hostname))
+//
+// Other errors returned by Fire are passed to error handler of the logger,
+// see Log.SetErrorHandler. Record is reused by Log after Fire returns, so it
+// should not be retained.
+type Hook interface {
+	Levels() []Level
+	Fire(record *Record) error
+}
+
+type hookFunc struct {
+	levels []Level
+	fire   func(record *Record) error
+}
+
+// NewHook returns Hook which calls given function for records of given
+// levels or for records of all levels if levels are not specified.
+func NewHook(fire func(record *Record) error, levels ...Level) Hook {
+	if len(levels) == 0 {
+		levels = AllLevels
+	}
+
+	return &hookFunc{levels: levels, fire: fire}
+}
+
+func (hook *hookFunc) Levels() []Level {
+	return hook.levels
+}
+
+func (hook *hookFunc) Fire(record *Record) error {
+	return hook.fire(record)
+}
+
+// AddHook adds hook which will be fired for records of given logger and all
+// it's children, hooks are fired in order of adding.
+func (log *Log) AddHook(hook Hook) {
+	log.mutex.Lock()
+
+	// hooks of logger created by WithFields are fired in addition to hooks
+	// of it's base, so they are not marked as set
+	log.setOptions(0, func(options *logOptions) {
+		for _, level := range hook.Levels() {
+			if level < LevelFatal || level > LevelTrace {
+				continue
+			}
+
+			// hooks are shared with previous options, so new slice is
+			// created
+			hooks := options.hooks[level]
+			options.hooks[level] = append(hooks[:len(hooks):len(hooks)], hook)
+		}
+	})
+
+	log.mutex.Unlock()
+}
+
+// SetErrorHandler sets function which will be called with errors of hooks,
+// outputs and flushing instead of printing them to stderr. Error handler is
+// set for all children of given logger too.
+func (log *Log) SetErrorHandler(handler func(error)) {
+	log.mutex.Lock()
+	log.setOptions(optionErrorHandler, func(options *logOptions) {
+		options.errorHandler = handler
+	})
+	log.mutex.Unlock()
+}
+
+// fireHooks fires hooks of record level and returns false if record should
+// be dropped.
+func (log *Log) fireHooks(record *Record) bool {
+	if record.Level < LevelFatal || record.Level > LevelTrace {
+		return true
+	}
+
+	hooks := log.getOptions().hooks[record.Level]
+	for _, hook := range hooks {
+		err := hook.Fire(record)
+		if err == nil {
+			continue
+		}
+
+		if errors.Is(err, ErrDropRecord) {
+			return false
+		}
+
+		log.handleError(fmt.Errorf("hook %T failed: %w", hook, err))
+	}
+
+	// hooks can change message or add fields, so record is redacted again
+	if len(hooks) != 0 {
+		log.redact(record)
+	}
+
+	return true
+}
+
+func (log *Log) handleError(err error) {
+	if handler := log.getOptions().errorHandler; handler != nil {
+		handler(err)
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, err)
+}
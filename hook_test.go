@@ -0,0 +1,149 @@
+package lorg
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLog_AddHook_FiresHooksForTheirLevels(t *testing.T) {
+	test := assert.New(t)
+
+	log := NewLog()
+	buffer := &bytes.Buffer{}
+	log.SetOutput(buffer)
+	log.SetFormat(NewFormat(`${level} %s${fields}`))
+	log.SetLevel(LevelDebug)
+
+	var errs []string
+	log.AddHook(NewHook(func(record *Record) error {
+		errs = append(errs, record.Message)
+		return nil
+	}, LevelError, LevelFatal))
+
+	log.AddHook(NewHook(func(record *Record) error {
+		record.Fields = record.Fields.Merge(NewFields("host", "a"))
+		return nil
+	}))
+
+	child := log.With("id", 1)
+
+	log.Info("info")
+	child.Error("error")
+	log.Debugf("debug")
+
+	test.Equal([]string{"error"}, errs)
+	test.Equal(
+		"INFO info host=a\nERROR error id=1 host=a\nDEBUG debug host=a\n",
+		buffer.String(),
+	)
+	test.Equal(Fields{{Key: "id", Value: 1}}, child.GetFields())
+}
+
+func TestLog_AddHook_FiresHooksOfWithChildAndParent(t *testing.T) {
+	test := assert.New(t)
+
+	log := NewLog()
+	log.SetOutput(&bytes.Buffer{})
+
+	var fired []string
+	hook := func(name string) Hook {
+		return NewHook(func(record *Record) error {
+			fired = append(fired, name+" "+record.Message)
+			return nil
+		})
+	}
+
+	child := log.With("id", 1)
+	child.AddHook(hook("child"))
+	log.AddHook(hook("parent"))
+
+	child.Info("a")
+	log.Info("b")
+
+	test.Equal([]string{"parent a", "child a", "parent b"}, fired)
+	test.Empty(log.children)
+}
+
+func TestLog_AddHook_DropsRecordsAndHandlesErrors(t *testing.T) {
+	test := assert.New(t)
+
+	log := NewLog()
+	buffer := &bytes.Buffer{}
+	log.SetOutput(buffer)
+	log.SetFormat(NewFormat(`%s`))
+
+	var handled []error
+	log.SetErrorHandler(func(err error) {
+		handled = append(handled, err)
+	})
+
+	failure := errors.New("tracker is down")
+
+	log.AddHook(NewHook(func(record *Record) error {
+		if record.Message == "noisy" {
+			return ErrDropRecord
+		}
+
+		return failure
+	}, LevelWarning))
+
+	log.Warning("noisy")
+	log.Warning("important")
+	log.Info("info")
+
+	test.Equal("important\ninfo\n", buffer.String())
+	test.Len(handled, 1)
+	test.ErrorIs(handled[0], failure)
+	test.Contains(handled[0].Error(), "hook *lorg.hookFunc failed")
+}
+
+func TestLog_SetErrorHandler_HandlesOutputErrors(t *testing.T) {
+	test := assert.New(t)
+
+	log := NewLog()
+	log.SetOutput(NewOutput(&failingWriter{}))
+
+	var handled []error
+	log.SetErrorHandler(func(err error) {
+		handled = append(handled, err)
+	})
+
+	log.NewChild().Info("message")
+
+	test.Len(handled, 1)
+	test.Contains(handled[0].Error(), "failed to write to log")
+}
+
+func TestLog_AddHook_IsSafeForConcurrentLogging(t *testing.T) {
+	log := NewLog()
+	log.SetOutput(io.Discard)
+
+	child := log.Named("db")
+
+	group := &sync.WaitGroup{}
+	group.Add(2)
+
+	go func() {
+		defer group.Done()
+
+		for i := 0; i < 1000; i++ {
+			log.AddHook(NewHook(func(*Record) error { return nil }))
+			log.SetErrorHandler(func(error) {})
+		}
+	}()
+
+	go func() {
+		defer group.Done()
+
+		for i := 0; i < 1000; i++ {
+			child.Info("concurrent")
+		}
+	}()
+
+	group.Wait()
+}
@@ -1,6 +1,20 @@
 package lorg
 
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 // Level describes all available log levels for log records.
+//
+// Level implements encoding.TextMarshaler, encoding.TextUnmarshaler,
+// json.Marshaler, json.Unmarshaler and flag.Value interfaces, so it can be
+// used in configuration structures and command line flags directly:
+//
+//	level := lorg.LevelInfo
+//	flag.Var(&level, "log-level", "logging level")
 type Level int
 
 const (
@@ -52,3 +66,78 @@ func (level Level) StringShort() string {
 
 	return level.String()
 }
+
+// ParseLevel returns logging level which is represented by given text, text
+// can be a full or short level name in any case (as returned by String and
+// StringShort) or a level number.
+func ParseLevel(text string) (Level, error) {
+	name := strings.ToUpper(strings.TrimSpace(text))
+
+	for level := LevelFatal; level <= LevelTrace; level++ {
+		if name == level.String() || name == level.StringShort() {
+			return level, nil
+		}
+	}
+
+	number, err := strconv.Atoi(name)
+	if err == nil && Level(number) >= LevelFatal &&
+		Level(number) <= LevelTrace {
+		return Level(number), nil
+	}
+
+	return 0, fmt.Errorf("unknown logging level: %q", text)
+}
+
+// MarshalText returns the full name of the level.
+func (level Level) MarshalText() ([]byte, error) {
+	if level < LevelFatal || level > LevelTrace {
+		return nil, fmt.Errorf("unknown logging level: %d", int(level))
+	}
+
+	return []byte(level.String()), nil
+}
+
+// UnmarshalText sets level which is represented by given text, see
+// ParseLevel.
+func (level *Level) UnmarshalText(text []byte) error {
+	parsed, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+
+	*level = parsed
+
+	return nil
+}
+
+// MarshalJSON returns the full name of the level as JSON string.
+func (level Level) MarshalJSON() ([]byte, error) {
+	text, err := level.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON sets level which is represented by given JSON string or
+// number.
+func (level *Level) UnmarshalJSON(data []byte) error {
+	var text string
+	if len(data) > 0 && data[0] == '"' {
+		err := json.Unmarshal(data, &text)
+		if err != nil {
+			return err
+		}
+	} else {
+		text = string(data)
+	}
+
+	return level.UnmarshalText([]byte(text))
+}
+
+// Set sets level which is represented by given text, it implements
+// flag.Value interface.
+func (level *Level) Set(text string) error {
+	return level.UnmarshalText([]byte(text))
+}
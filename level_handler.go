@@ -0,0 +1,153 @@
+package lorg
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// ensure that LevelHandler implements http.Handler interface.
+var _ http.Handler = (*LevelHandler)(nil)
+
+// LevelHandler is the http.Handler which allows to get and change logging
+// level of a logger at runtime:
+//
+//	http.Handle("/log/level", lorg.NewLevelHandler(log))
+//
+// GET request returns current level, PUT request changes level to the level
+// specified in the request body. Level is passed as plain text (INFO) or as
+// JSON object ({"level":"INFO"}) if request has application/json content
+// type or accepts it.
+//
+// Level of a child logger can be accessed by passing the name of the logger
+// created using Named or the child prefix in the "logger" query parameter,
+// the level is changed for all children with given prefix. Level changes
+// propagate to children like SetLevel does.
+type LevelHandler struct {
+	log *Log
+}
+
+type levelHandlerBody struct {
+	Level Level `json:"level"`
+}
+
+// NewLevelHandler creates LevelHandler for given logger or for package
+// logger if given logger is nil.
+func NewLevelHandler(log *Log) *LevelHandler {
+	if log == nil {
+		log = logger
+	}
+
+	return &LevelHandler{log: log}
+}
+
+// ServeHTTP handles GET and PUT requests.
+func (handler *LevelHandler) ServeHTTP(
+	response http.ResponseWriter, request *http.Request,
+) {
+	logs := []*Log{handler.log}
+	if name := request.URL.Query().Get("logger"); name != "" {
+		if named := handler.log.Lookup(name); named != nil {
+			logs = []*Log{named}
+		} else {
+			logs = handler.log.findChildren(name)
+		}
+
+		if len(logs) == 0 {
+			http.Error(response, "logger not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	switch request.Method {
+	case http.MethodGet, http.MethodHead:
+
+	case http.MethodPut:
+		level, err := readLevel(request)
+		if err != nil {
+			http.Error(response, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		for _, log := range logs {
+			log.SetLevel(level)
+		}
+
+	default:
+		response.Header().Set("Allow", "GET, HEAD, PUT")
+		http.Error(
+			response, "method not allowed", http.StatusMethodNotAllowed,
+		)
+		return
+	}
+
+	level := logs[0].GetLevel()
+
+	if isJSONRequest(request) {
+		response.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(response).Encode(levelHandlerBody{Level: level})
+		return
+	}
+
+	response.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	io.WriteString(response, level.String()+"\n")
+}
+
+func readLevel(request *http.Request) (Level, error) {
+	data, err := io.ReadAll(io.LimitReader(request.Body, 1024))
+	if err != nil {
+		return 0, err
+	}
+
+	contentType, _, _ := mime.ParseMediaType(
+		request.Header.Get("Content-Type"),
+	)
+	if contentType == "application/json" {
+		var body struct {
+			Level *Level `json:"level"`
+		}
+
+		err := json.Unmarshal(data, &body)
+		if err != nil {
+			return 0, err
+		}
+
+		if body.Level == nil {
+			return 0, errors.New("level is not specified")
+		}
+
+		return *body.Level, nil
+	}
+
+	return ParseLevel(string(data))
+}
+
+func isJSONRequest(request *http.Request) bool {
+	return strings.Contains(request.Header.Get("Accept"), "application/json") ||
+		strings.HasPrefix(
+			request.Header.Get("Content-Type"), "application/json",
+		)
+}
+
+// findChildren returns the closest children (not descendants of each other)
+// of given logger with given prefix.
+func (log *Log) findChildren(prefix string) []*Log {
+	log.mutex.Lock()
+	children := append([]*Log(nil), log.children...)
+	log.mutex.Unlock()
+
+	var found []*Log
+	for _, child := range children {
+		if child.prefix == prefix {
+			found = append(found, child)
+			continue
+		}
+
+		found = append(found, child.findChildren(prefix)...)
+	}
+
+	return found
+}
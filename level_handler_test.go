@@ -0,0 +1,91 @@
+package lorg
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevelHandler_ServeHTTP_GetsAndSetsLevel(t *testing.T) {
+	test := assert.New(t)
+
+	log := NewLog()
+	child := log.NewChild()
+
+	handler := NewLevelHandler(log)
+
+	response := serveLevel(handler, http.MethodGet, "/", "", "")
+	test.Equal(http.StatusOK, response.Code)
+	test.Equal("INFO\n", response.Body.String())
+
+	response = serveLevel(handler, http.MethodPut, "/", "", "debug")
+	test.Equal(http.StatusOK, response.Code)
+	test.Equal("DEBUG\n", response.Body.String())
+	test.Equal(LevelDebug, log.GetLevel())
+	test.Equal(LevelDebug, child.GetLevel())
+
+	response = serveLevel(
+		handler, http.MethodPut, "/", "application/json", `{"level":"warn"}`,
+	)
+	test.Equal(http.StatusOK, response.Code)
+	test.JSONEq(`{"level":"WARNING"}`, response.Body.String())
+	test.Equal(LevelWarning, child.GetLevel())
+
+	response = serveLevel(
+		handler, http.MethodPut, "/", "application/json", `{}`,
+	)
+	test.Equal(http.StatusBadRequest, response.Code)
+
+	response = serveLevel(handler, http.MethodPut, "/", "", "verbose")
+	test.Equal(http.StatusBadRequest, response.Code)
+	test.Equal(LevelWarning, log.GetLevel())
+
+	response = serveLevel(handler, http.MethodPost, "/", "", "info")
+	test.Equal(http.StatusMethodNotAllowed, response.Code)
+}
+
+func TestLevelHandler_ServeHTTP_ChangesLevelOfNamedChildren(t *testing.T) {
+	test := assert.New(t)
+
+	log := NewLog()
+	database := log.NewChildWithPrefix("db")
+	query := database.With("query", 1)
+	server := log.NewChildWithPrefix("server")
+
+	handler := NewLevelHandler(log)
+
+	response := serveLevel(
+		handler, http.MethodPut, "/?logger=db", "", "trace",
+	)
+	test.Equal(http.StatusOK, response.Code)
+
+	test.Equal(LevelInfo, log.GetLevel())
+	test.Equal(LevelTrace, database.GetLevel())
+	test.Equal(LevelTrace, query.GetLevel())
+	test.Equal(LevelInfo, server.GetLevel())
+
+	response = serveLevel(handler, http.MethodGet, "/?logger=db", "", "")
+	test.Equal("TRACE\n", response.Body.String())
+
+	response = serveLevel(
+		handler, http.MethodGet, "/?logger=cache", "", "",
+	)
+	test.Equal(http.StatusNotFound, response.Code)
+}
+
+func serveLevel(
+	handler http.Handler, method, target, contentType, body string,
+) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+
+	return response
+}
@@ -0,0 +1,57 @@
+//go:build unix
+
+package lorg
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// HandleLevelSignals changes logging level of given logger after receiving
+// signals:
+//   - SIGUSR1 cycles level to the next more verbose level, LevelTrace is
+//     followed by LevelFatal;
+//   - SIGUSR2 toggles level between given level and the level which has been
+//     set before toggling.
+//
+// Level changes propagate to children like SetLevel does. HandleLevelSignals
+// returns function which stops handling signals.
+func (log *Log) HandleLevelSignals(toggle Level) func() {
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+
+	signal.Notify(signals, syscall.SIGUSR1, syscall.SIGUSR2)
+
+	go func() {
+		previous := log.GetLevel()
+
+		for {
+			select {
+			case sig := <-signals:
+				level := log.GetLevel()
+
+				switch sig {
+				case syscall.SIGUSR1:
+					log.SetLevel((level + 1) % (LevelTrace + 1))
+
+				case syscall.SIGUSR2:
+					if level == toggle {
+						log.SetLevel(previous)
+					} else {
+						previous = level
+						log.SetLevel(toggle)
+					}
+				}
+
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package lorg
+
+// HandleLevelSignals does nothing on systems without SIGUSR1 and SIGUSR2
+// signals, it returns function which does nothing.
+func (log *Log) HandleLevelSignals(toggle Level) func() {
+	return func() {}
+}
@@ -0,0 +1,34 @@
+//go:build unix
+
+package lorg
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLog_HandleLevelSignals_CyclesAndTogglesLevel(t *testing.T) {
+	test := assert.New(t)
+
+	log := NewLog()
+	child := log.NewChild()
+
+	stop := log.HandleLevelSignals(LevelTrace)
+	defer stop()
+
+	expectLevel := func(sig syscall.Signal, level Level) {
+		test.NoError(syscall.Kill(syscall.Getpid(), sig))
+		test.Eventually(func() bool {
+			return child.GetLevel() == level
+		}, time.Second, time.Millisecond)
+	}
+
+	expectLevel(syscall.SIGUSR1, LevelDebug)
+	expectLevel(syscall.SIGUSR1, LevelTrace)
+	expectLevel(syscall.SIGUSR1, LevelFatal)
+	expectLevel(syscall.SIGUSR2, LevelTrace)
+	expectLevel(syscall.SIGUSR2, LevelFatal)
+}
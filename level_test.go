@@ -0,0 +1,89 @@
+package lorg
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLevel_AcceptsNamesAndNumbers(t *testing.T) {
+	test := assert.New(t)
+
+	for text, expected := range map[string]Level{
+		"fatal":   LevelFatal,
+		"ERROR":   LevelError,
+		"Warning": LevelWarning,
+		"warn":    LevelWarning,
+		" info ":  LevelInfo,
+		"debug":   LevelDebug,
+		"5":       LevelTrace,
+	} {
+		level, err := ParseLevel(text)
+		test.NoError(err, text)
+		test.Equal(expected, level, text)
+	}
+
+	for _, text := range []string{"", "verbose", "6", "-1"} {
+		_, err := ParseLevel(text)
+		test.Error(err, text)
+	}
+}
+
+func TestLevel_ImplementsFlagValueInterface(t *testing.T) {
+	test := assert.New(t)
+
+	level := LevelInfo
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.Var(&level, "log-level", "")
+
+	test.NoError(flags.Parse([]string{"--log-level=debug"}))
+	test.Equal(LevelDebug, level)
+
+	test.Error(flags.Parse([]string{"--log-level=verbose"}))
+}
+
+func TestLevel_MarshalJSON_UsesNames(t *testing.T) {
+	test := assert.New(t)
+
+	var config struct {
+		Level  Level `json:"level"`
+		Levels []Level
+	}
+
+	test.NoError(json.Unmarshal(
+		[]byte(`{"level":"warn","Levels":[1,"trace"]}`), &config,
+	))
+	test.Equal(LevelWarning, config.Level)
+	test.Equal([]Level{LevelError, LevelTrace}, config.Levels)
+
+	data, err := json.Marshal(config)
+	test.NoError(err)
+	test.Equal(
+		`{"level":"WARNING","Levels":["ERROR","TRACE"]}`, string(data),
+	)
+
+	_, err = json.Marshal(Level(10))
+	test.Error(err)
+}
+
+func TestLog_SetLevelFromEnv_SetsLevelIfVariableIsSet(t *testing.T) {
+	test := assert.New(t)
+
+	log := NewLog()
+
+	test.NoError(log.SetLevelFromEnv("LORG_TEST_LEVEL"))
+	test.Equal(LevelInfo, log.GetLevel())
+
+	t.Setenv("LORG_TEST_LEVEL", "trace")
+	test.NoError(log.SetLevelFromEnv("LORG_TEST_LEVEL"))
+	test.Equal(LevelTrace, log.GetLevel())
+
+	t.Setenv("LORG_TEST_LEVEL", "loud")
+	test.Error(log.SetLevelFromEnv("LORG_TEST_LEVEL"))
+	test.Equal(LevelTrace, log.GetLevel())
+}
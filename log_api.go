@@ -1,9 +1,12 @@
 package lorg
 
 import (
+	"context"
+	"fmt"
 	"io"
 	"os"
 	"sync"
+	"sync/atomic"
 )
 
 const (
@@ -11,15 +14,16 @@ const (
 	// If you don't run SetFormat, Log instance will use Format instance with
 	// this given formatting.
 	//
-	// See Format structure documentation for information about `${date}` and
-	// `${level}` placeholders.
-	DefaultFormatting = `${time} ${level:[%s]\::right:true} ${prefix}%s`
+	// See Format structure documentation for information about `${date}`,
+	// `${level}` and `${fields}` placeholders.
+	DefaultFormatting = `${time} ${level:[%s]\::right:true} ${prefix}%s${fields}${stack}`
 )
 
 var (
 	defaultLevel  = LevelInfo
 	defaultFormat = NewFormat(DefaultFormatting)
 	defaultOutput = NewOutput(os.Stderr)
+	defaultColors = isColorOutput(defaultOutput)
 
 	// Exiter will be called after Fatal/Fatalf invocation.
 	Exiter = os.Exit
@@ -32,16 +36,69 @@ var (
 // because Log fields can be changed or added other fields, so it can provide
 // bugs in future.
 type Log struct {
-	level Level
+	// level is accessed atomically, so logging functions check level without
+	// locking mutex.
+	level atomic.Int32
 
 	output      SmartOutput
+	colors      bool
 	format      Formatter
 	indentLines bool
 	shiftIndent int
 	mutex       *sync.Mutex
 	children    []*Log
 	prefix      string
+	fields      Fields
 	exiter      func(int)
+	callerSkip  int
+
+	// options are replaced atomically, so logging functions read them
+	// without locking mutex.
+	options atomic.Pointer[logOptions]
+
+	name     string
+	parent   *Log
+	pinned   bool
+	registry *loggerRegistry
+
+	// base is the logger which given logger has been created from by
+	// WithFields, level and options of base are used unless they are changed
+	// for given logger, so such loggers are not kept in children of base.
+	base     *Log
+	levelSet atomic.Bool
+	merged   atomic.Pointer[mergedOptions]
+}
+
+// logOptions contains options of Log which are inherited by children, options
+// are never changed after storing, they are copied and replaced instead.
+type logOptions struct {
+	hooks        [LevelTrace + 1][]Hook
+	errorHandler func(error)
+	sampler      *Sampler
+	redactor     *Redactor
+	stackLevel   Level
+
+	// set contains options which are changed for logger created by
+	// WithFields, other options are taken from it's base.
+	set optionSet
+}
+
+// optionSet is a bit mask of logOptions fields.
+type optionSet int
+
+const (
+	optionErrorHandler optionSet = 1 << iota
+	optionSampler
+	optionRedactor
+	optionStackLevel
+)
+
+// mergedOptions caches options of logger created by WithFields, which are
+// merged from options of the base and own options of the logger.
+type mergedOptions struct {
+	base    *logOptions
+	own     *logOptions
+	options *logOptions
 }
 
 // NewLog creates a new Log instance with default configuration:
@@ -54,13 +111,17 @@ type Log struct {
 //     using log.SetOutput(io.Writer) method
 func NewLog() *Log {
 	log := &Log{
-		level:  defaultLevel,
 		format: defaultFormat,
 		output: defaultOutput,
+		colors: defaultColors,
 		mutex:  &sync.Mutex{},
 		exiter: Exiter,
 	}
 
+	log.level.Store(int32(defaultLevel))
+	log.options.Store(&logOptions{stackLevel: levelNone})
+	log.registry = newLoggerRegistry(log)
+
 	return log
 }
 
@@ -80,25 +141,159 @@ func (log *Log) SetExiter(exiter func(int)) {
 // Running SetLevel it's not required operation, by default Log instance
 // creates with INFO level, so levels above (warn, err, fatal, info) will be
 // logged also.
+//
+// Level is propagated to children except named children which levels are
+// set by SetLevelSpec, see Named.
 func (log *Log) SetLevel(level Level) {
 	log.mutex.Lock()
+	log.setLevel(level)
+	log.mutex.Unlock()
+}
 
-	log.level = level
+// setLevel sets level and propagates it to children except children which
+// levels are pinned by level spec, log mutex should be locked.
+func (log *Log) setLevel(level Level) {
+	log.level.Store(int32(level))
+	if log.base != nil {
+		log.levelSet.Store(true)
+	}
 
 	for _, child := range log.children {
-		child.SetLevel(level)
+		child.mutex.Lock()
+		if !child.pinned {
+			child.setLevel(level)
+		}
+		child.mutex.Unlock()
 	}
+}
 
-	log.mutex.Unlock()
+// getOptions returns options which are used by given logger.
+func (log *Log) getOptions() *logOptions {
+	own := log.options.Load()
+	if log.base == nil {
+		return own
+	}
+
+	base := log.base.getOptions()
+	if own == nil {
+		return base
+	}
+
+	merged := log.merged.Load()
+	if merged != nil && merged.base == base && merged.own == own {
+		return merged.options
+	}
+
+	merged = &mergedOptions{
+		base:    base,
+		own:     own,
+		options: mergeOptions(base, own),
+	}
+	log.merged.Store(merged)
+
+	return merged.options
+}
+
+// mergeOptions returns options of base overridden by options which are set
+// in own options, hooks of own options are appended to hooks of base.
+func mergeOptions(base, own *logOptions) *logOptions {
+	options := *base
+
+	for level, hooks := range own.hooks {
+		if len(hooks) > 0 {
+			// slice of base is limited by it's length, so appending
+			// creates new slice
+			inherited := base.hooks[level]
+			options.hooks[level] = append(
+				inherited[:len(inherited):len(inherited)], hooks...,
+			)
+		}
+	}
+
+	if own.set&optionErrorHandler != 0 {
+		options.errorHandler = own.errorHandler
+	}
+
+	if own.set&optionSampler != 0 {
+		options.sampler = own.sampler
+	}
+
+	if own.set&optionRedactor != 0 {
+		options.redactor = own.redactor
+	}
+
+	if own.set&optionStackLevel != 0 {
+		options.stackLevel = own.stackLevel
+	}
+
+	return &options
+}
+
+// setOptions changes options of given logger and all it's children using
+// given function which receives copy of options, set specifies changed
+// options, log mutex should be locked.
+func (log *Log) setOptions(set optionSet, change func(options *logOptions)) {
+	log.propagate(func(log *Log) {
+		var options logOptions
+		if current := log.options.Load(); current != nil {
+			options = *current
+		}
+
+		change(&options)
+
+		if log.base != nil {
+			options.set |= set
+		}
+
+		log.options.Store(&options)
+	})
+}
+
+// propagate calls given function for given logger and all it's children,
+// it's used for options which are inherited by children, log mutex should be
+// locked.
+func (log *Log) propagate(set func(*Log)) {
+	set(log)
+
+	for _, child := range log.children {
+		child.mutex.Lock()
+		child.propagate(set)
+		child.mutex.Unlock()
+	}
+}
+
+// SetLevelFromEnv sets the logging level which is specified by environment
+// variable with given name, see ParseLevel for accepted values. Level is not
+// changed if variable is not set or empty.
+//
+//	err := log.SetLevelFromEnv("LORG_LEVEL")
+func (log *Log) SetLevelFromEnv(name string) error {
+	value := os.Getenv(name)
+	if value == "" {
+		return nil
+	}
+
+	level, err := ParseLevel(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", name, err)
+	}
+
+	log.SetLevel(level)
+
+	return nil
 }
 
 // GetLevel returns the logging level for the given logger.
 func (log *Log) GetLevel() Level {
-	log.mutex.Lock()
-	level := log.level
-	log.mutex.Unlock()
+	return log.getLevel()
+}
 
-	return level
+func (log *Log) getLevel() Level {
+	for log.base != nil && !log.levelSet.Load() {
+		log = log.base
+	}
+
+	return Level(log.level.Load())
 }
 
 // SetFormat sets the logging format for the given log.
@@ -119,6 +314,9 @@ func (log *Log) SetFormat(format Formatter) {
 //
 // Running SetOutput it's not required operation, by default Log instance
 // logs all records to stderr (os.Stderr)
+//
+// Color placeholders are rendered only if writers of given output are
+// terminals at the moment of calling SetOutput, see PlaceholderColor.
 func (log *Log) SetOutput(output io.Writer) {
 	log.mutex.Lock()
 
@@ -127,6 +325,7 @@ func (log *Log) SetOutput(output io.Writer) {
 	}
 
 	log.output = output.(SmartOutput)
+	log.colors = isColorOutput(output)
 
 	log.mutex.Unlock()
 }
@@ -154,10 +353,12 @@ func (log *Log) SetShiftIndent(shift int) {
 }
 
 // Fatal logs record if given logger level is equal or above LevelFatal, and
-// calls os.Exit(1) after logging.
+// calls os.Exit(1) after logging and flushing output if it implements
+// Flusher.
 // Arguments are handled in the manner of fmt.Print.
 func (log *Log) Fatal(value ...interface{}) {
 	log.log(LevelFatal, value...)
+	log.flush()
 	log.exiter(1)
 }
 
@@ -166,6 +367,7 @@ func (log *Log) Fatal(value ...interface{}) {
 // Arguments are handled in the manner of fmt.Print.
 func (log *Log) Fatalf(format string, value ...interface{}) {
 	log.logf(LevelFatal, format, value...)
+	log.flush()
 	log.exiter(1)
 }
 
@@ -239,21 +441,141 @@ func (log *Log) Tracef(format string, value ...interface{}) {
 	log.logf(LevelTrace, format, value...)
 }
 
+// Fatalw logs record with given message and fields if given logger level is
+// equal or above LevelFatal, and calls os.Exit(1) after logging.
+// Fields are passed as alternating keys and values like in With.
+func (log *Log) Fatalw(message string, keyvalues ...interface{}) {
+	log.logw(LevelFatal, message, keyvalues...)
+	log.flush()
+	log.exiter(1)
+}
+
+// Errorw logs record with given message and fields if given logger level is
+// equal or above LevelError.
+// Fields are passed as alternating keys and values like in With.
+func (log *Log) Errorw(message string, keyvalues ...interface{}) {
+	log.logw(LevelError, message, keyvalues...)
+}
+
+// Warningw logs record with given message and fields if given logger level
+// is equal or above LevelWarning.
+// Fields are passed as alternating keys and values like in With.
+func (log *Log) Warningw(message string, keyvalues ...interface{}) {
+	log.logw(LevelWarning, message, keyvalues...)
+}
+
+// Infow logs record with given message and fields if given logger level is
+// equal or above LevelInfo.
+// Fields are passed as alternating keys and values like in With.
+func (log *Log) Infow(message string, keyvalues ...interface{}) {
+	log.logw(LevelInfo, message, keyvalues...)
+}
+
+// Debugw logs record with given message and fields if given logger level is
+// equal or above LevelDebug.
+// Fields are passed as alternating keys and values like in With.
+func (log *Log) Debugw(message string, keyvalues ...interface{}) {
+	log.logw(LevelDebug, message, keyvalues...)
+}
+
+// Tracew logs record with given message and fields if given logger level is
+// equal or above LevelTrace.
+// Fields are passed as alternating keys and values like in With.
+func (log *Log) Tracew(message string, keyvalues ...interface{}) {
+	log.logw(LevelTrace, message, keyvalues...)
+}
+
+// FatalContext logs record with given message, fields extracted from given
+// context and given fields if given logger level is equal or above LevelFatal,
+// and calls os.Exit(1) after logging.
+// Fields are passed as alternating keys and values like in With.
+func (log *Log) FatalContext(
+	ctx context.Context, message string, keyvalues ...interface{},
+) {
+	log.logContext(ctx, LevelFatal, message, keyvalues...)
+	log.flush()
+	log.exiter(1)
+}
+
+// ErrorContext logs record with given message, fields extracted from given
+// context and given fields if given logger level is equal or above LevelError.
+// Fields are passed as alternating keys and values like in With.
+func (log *Log) ErrorContext(
+	ctx context.Context, message string, keyvalues ...interface{},
+) {
+	log.logContext(ctx, LevelError, message, keyvalues...)
+}
+
+// WarningContext logs record with given message, fields extracted from
+// given context and given fields if given logger level is equal or above
+// LevelWarning.
+// Fields are passed as alternating keys and values like in With.
+func (log *Log) WarningContext(
+	ctx context.Context, message string, keyvalues ...interface{},
+) {
+	log.logContext(ctx, LevelWarning, message, keyvalues...)
+}
+
+// InfoContext logs record with given message, fields extracted from given
+// context and given fields if given logger level is equal or above LevelInfo.
+// Fields are passed as alternating keys and values like in With.
+func (log *Log) InfoContext(
+	ctx context.Context, message string, keyvalues ...interface{},
+) {
+	log.logContext(ctx, LevelInfo, message, keyvalues...)
+}
+
+// DebugContext logs record with given message, fields extracted from given
+// context and given fields if given logger level is equal or above LevelDebug.
+// Fields are passed as alternating keys and values like in With.
+func (log *Log) DebugContext(
+	ctx context.Context, message string, keyvalues ...interface{},
+) {
+	log.logContext(ctx, LevelDebug, message, keyvalues...)
+}
+
+// TraceContext logs record with given message, fields extracted from given
+// context and given fields if given logger level is equal or above LevelTrace.
+// Fields are passed as alternating keys and values like in With.
+func (log *Log) TraceContext(
+	ctx context.Context, message string, keyvalues ...interface{},
+) {
+	log.logContext(ctx, LevelTrace, message, keyvalues...)
+}
+
 // SetPrefix of given logger, prefix placeholder should be used in logger
 // format.
 func (log *Log) SetPrefix(prefix string) {
 	log.prefix = prefix
 }
 
-// NewChild of given logger, child inherit level, format and output options.
+// NewChild of given logger, child inherit level, format, output and exiter
+// options.
 func (log *Log) NewChild() *Log {
+	// given logger is not kept in children of it's base, so child follows
+	// given logger the same way
+	if log.base != nil {
+		child := log.WithFields(nil)
+		child.prefix = ""
+		child.shiftIndent = 0
+		child.name = ""
+
+		return child
+	}
+
 	log.mutex.Lock()
 
 	child := NewLog()
-	child.SetOutput(log.output)
-	child.SetLevel(log.level)
+	child.output = log.output
+	child.colors = log.colors
+	child.SetLevel(log.getLevel())
 	child.SetFormat(log.format)
 	child.SetIndentLines(log.indentLines)
+	child.fields = log.fields
+	child.registry = log.registry
+	child.callerSkip = log.callerSkip
+	child.exiter = log.exiter
+	child.options.Store(log.options.Load())
 
 	log.children = append(log.children, child)
 
@@ -269,3 +591,50 @@ func (log *Log) NewChildWithPrefix(prefix string) *Log {
 	child.SetPrefix(prefix)
 	return child
 }
+
+// With returns a child of given logger which attaches given fields to every
+// log record. Arguments are alternating keys and values:
+//
+//	log.With("user", id, "request", requestID).Info("authorized")
+//
+// Fields of the child are merged with the fields of given logger, so child
+// values override parent values with same keys. Child inherits level,
+// format, output and prefix options.
+//
+// Child is not kept by given logger, so it can be created for every request.
+// Child follows level, error handler, sampler, redactor and stack trace level
+// of given logger unless they are changed for the child, hooks which are
+// added to the child are fired in addition to hooks of given logger.
+func (log *Log) With(keyvalues ...interface{}) *Log {
+	return log.WithFields(NewFields(keyvalues...))
+}
+
+// WithFields is the same as With, but takes already built Fields.
+func (log *Log) WithFields(fields Fields) *Log {
+	log.mutex.Lock()
+
+	child := &Log{
+		output:      log.output,
+		colors:      log.colors,
+		format:      log.format,
+		indentLines: log.indentLines,
+		shiftIndent: log.shiftIndent,
+		mutex:       &sync.Mutex{},
+		prefix:      log.prefix,
+		fields:      log.fields.Merge(fields),
+		exiter:      log.exiter,
+		callerSkip:  log.callerSkip,
+		name:        log.name,
+		registry:    log.registry,
+		base:        log,
+	}
+
+	log.mutex.Unlock()
+
+	return child
+}
+
+// GetFields returns fields which are attached to given logger.
+func (log *Log) GetFields() Fields {
+	return log.fields
+}
@@ -2,6 +2,7 @@ package lorg
 
 import (
 	"bytes"
+	"io"
 	stdlog "log"
 	"sync"
 	"testing"
@@ -56,3 +57,96 @@ func BenchmarkStdLog_Printf(b *testing.B) {
 		log.Printf("%v", logString)
 	}
 }
+
+func BenchmarkLog_Info(b *testing.B) {
+	log := NewLog()
+	log.SetOutput(io.Discard)
+
+	benchmarkWithoutAllocs(b, func() {
+		log.Info("lorg")
+	})
+}
+
+func BenchmarkLog_Info_Placeholders(b *testing.B) {
+	log := NewLog()
+	log.SetOutput(io.Discard)
+	log.SetFormat(NewFormat(
+		`${time:15:04:05.000} ${color}${level:%s:left:true}${reset} ` +
+			`${pid} ${file}:${line} ${prefix}%s${fields}`,
+	))
+	log.SetPrefix("prefix")
+
+	benchmarkWithoutAllocs(b, func() {
+		log.Info("lorg")
+	})
+}
+
+func BenchmarkLog_Info_Fields(b *testing.B) {
+	log := NewLog()
+	log.SetOutput(io.Discard)
+
+	log = log.With("user", "root", "id", 1)
+
+	benchmarkWithoutAllocs(b, func() {
+		log.Info("lorg")
+	})
+}
+
+func BenchmarkLog_Infof_NoArgs(b *testing.B) {
+	log := NewLog()
+	log.SetOutput(io.Discard)
+
+	benchmarkWithoutAllocs(b, func() {
+		log.Infof("lorg")
+	})
+}
+
+func BenchmarkLog_Debug_Disabled(b *testing.B) {
+	log := NewLog()
+	log.SetOutput(io.Discard)
+
+	benchmarkWithoutAllocs(b, func() {
+		log.Debugf("%s %d", "lorg", 1)
+	})
+}
+
+func BenchmarkLog_Info_Parallel(b *testing.B) {
+	log := NewLog()
+	log.SetOutput(io.Discard)
+
+	b.ReportAllocs()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			log.Info("lorg")
+		}
+	})
+}
+
+func BenchmarkLog_Infow(b *testing.B) {
+	log := NewLog()
+	log.SetOutput(io.Discard)
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		log.Infow("lorg", "user", "root", "id", i)
+	}
+}
+
+// benchmarkWithoutAllocs benchmarks given function and fails if it
+// allocates.
+func benchmarkWithoutAllocs(b *testing.B, function func()) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		function()
+	}
+
+	b.StopTimer()
+
+	allocs := testing.AllocsPerRun(100, function)
+	if allocs != 0 {
+		b.Errorf("expected no allocations, got %v per run", allocs)
+	}
+}
@@ -44,7 +44,7 @@ func TestNewLog_ReturnsLogWithDefaultFields(t *testing.T) {
 	log := NewLog()
 
 	test.Equal(
-		defaultLevel, log.level,
+		defaultLevel, log.GetLevel(),
 		"Log object created with wrong default logging level",
 	)
 
@@ -82,7 +82,7 @@ func TestLog_SetLevel_ChangesLevelField(t *testing.T) {
 	log := NewLog()
 	log.SetLevel(LevelWarning)
 
-	test.Equal(LevelWarning, log.level)
+	test.Equal(LevelWarning, log.GetLevel())
 }
 
 func TestLog_GetLevel_ReturnsCurrentLevel(t *testing.T) {
@@ -190,7 +190,7 @@ func TestLog_NewChild_InheritsLevelValue(t *testing.T) {
 	log.SetLevel(LevelDebug)
 
 	child := log.NewChild()
-	test.Equal(child.level, log.level)
+	test.Equal(child.GetLevel(), log.GetLevel())
 }
 
 func TestLog_NewChild_InheritsOutputValue(t *testing.T) {
@@ -211,8 +211,8 @@ func TestLog_SetLevel_ChangesChildrenLevelField(t *testing.T) {
 
 	log.SetLevel(LevelTrace)
 
-	test.Equal(child.level, log.level)
-	test.Equal(child2.level, log.level)
+	test.Equal(child.GetLevel(), log.GetLevel())
+	test.Equal(child2.GetLevel(), log.GetLevel())
 }
 
 func TestLog_NewChild_ChildCantChangeParentLevel(t *testing.T) {
@@ -224,7 +224,7 @@ func TestLog_NewChild_ChildCantChangeParentLevel(t *testing.T) {
 	log.SetLevel(LevelTrace)
 	child.SetLevel(LevelDebug)
 
-	test.NotEqual(child.level, log.level)
+	test.NotEqual(child.GetLevel(), log.GetLevel())
 }
 
 func TestLog_NewChild_ChildRunsExiter(t *testing.T) {
@@ -334,3 +334,79 @@ func address(target interface{}) uintptr {
 
 	return value.UnsafeAddr()
 }
+
+func TestLog_With_RendersFieldsOfParentAndChild(t *testing.T) {
+	test := assert.New(t)
+
+	var buffer bytes.Buffer
+
+	log := NewLog()
+	log.SetOutput(&buffer)
+	log.SetFormat(NewFormat(`${prefix}%s${fields}`))
+	log.SetPrefix("root")
+
+	child := log.With("user", "alice", "request", 1)
+	subchild := child.With("request", 2, "step", "auth")
+
+	log.Info("1")
+	child.Info("2")
+	subchild.Infow("3", "step", "done", "size", 10)
+	child.Info("4")
+
+	test.Equal(
+		"root 1\n"+
+			"root 2 user=alice request=1\n"+
+			"root 3 user=alice request=2 step=done size=10\n"+
+			"root 4 user=alice request=1\n",
+		buffer.String(),
+	)
+}
+
+func TestLog_With_ChildFieldsSurviveSetLevel(t *testing.T) {
+	test := assert.New(t)
+
+	var buffer bytes.Buffer
+
+	log := NewLog()
+	log.SetOutput(&buffer)
+	log.SetFormat(NewFormat(`${level} %s${fields}`))
+
+	child := log.With("module", "db")
+
+	log.SetLevel(LevelDebug)
+	child.Debugw("query", "rows", 3)
+
+	test.Equal(LevelDebug, child.GetLevel())
+	test.Equal("DEBUG query module=db rows=3\n", buffer.String())
+}
+
+func TestLog_With_ChildIsNotKeptByParent(t *testing.T) {
+	test := assert.New(t)
+
+	log := NewLog()
+	log.SetOutput(&bytes.Buffer{})
+
+	for i := 0; i < 1000; i++ {
+		log.With("request", i).WithCallerSkip(1).Info("handled")
+	}
+
+	test.Empty(log.children)
+
+	for i := 0; i < 1000; i++ {
+		log.With("request", i).NewChildWithPrefix("[db]").Info("queried")
+	}
+
+	test.Empty(log.children)
+
+	child := log.With("module", "db")
+	child.SetLevel(LevelTrace)
+
+	test.Equal(LevelTrace, child.GetLevel())
+	test.Equal(LevelInfo, log.GetLevel())
+	test.Empty(log.children)
+
+	log.SetLevel(LevelWarning)
+
+	test.Equal(LevelTrace, child.GetLevel())
+	test.Equal(LevelWarning, log.With("module", "http").NewChild().GetLevel())
+}
@@ -1,31 +1,220 @@
 package lorg
 
 import (
+	"context"
 	"fmt"
-	"os"
 	"strings"
+	"sync"
+	"time"
+)
+
+// recordCallStackLevel is the argument to caller in doLog which points to the
+// caller of logging function: doLog <- log <- Info <- caller.
+const recordCallStackLevel = 3
+
+// maxPooledBufferSize is the maximum capacity of buffers which are returned
+// to bufferPool, so huge records don't hold memory forever.
+const maxPooledBufferSize = 64 << 10
+
+var (
+	bufferPool = sync.Pool{
+		New: func() interface{} {
+			buffer := make([]byte, 0, 256)
+			return &buffer
+		},
+	}
+
+	// recordPool contains records which are reused by doLog, so records
+	// should not be retained by outputs and formatters.
+	recordPool = sync.Pool{
+		New: func() interface{} {
+			return &Record{}
+		},
+	}
 )
 
 func (log *Log) log(level Level, value ...interface{}) {
-	if log.level < level {
+	if log.getLevel() < level {
 		return
 	}
 
-	log.doLog(level, value...)
+	value = redactableValues(value)
+	message := sprint(value)
+
+	log.doLog(nil, level, nil, message, message, value)
 }
 
 func (log *Log) logf(level Level, format string, value ...interface{}) {
-	if log.level < level {
+	if log.getLevel() < level {
+		return
+	}
+
+	// there is nothing to format, so message can be used as is
+	if len(value) == 0 && strings.IndexByte(format, '%') < 0 {
+		log.doLog(nil, level, nil, format, format, nil)
+		return
+	}
+
+	value = redactableValues(value)
+
+	log.doLog(
+		nil, level, nil, format, fmt.Sprintf(format, value...), value,
+	)
+}
+
+func (log *Log) logw(level Level, message string, keyvalues ...interface{}) {
+	if log.getLevel() < level {
+		return
+	}
+
+	log.doLog(nil, level, NewFields(keyvalues...), message, message, nil)
+}
+
+func (log *Log) logContext(
+	ctx context.Context, level Level, message string,
+	keyvalues ...interface{},
+) {
+	if log.getLevel() < level {
+		return
+	}
+
+	log.doLog(
+		ctx, level, contextFields(ctx).Merge(NewFields(keyvalues...)),
+		message, message, nil,
+	)
+}
+
+// doLog logs record with given message, template is the format of the
+// message which is used by sampler for grouping similar records, values are
+// arguments of logging function which are checked for errors with stacks.
+func (log *Log) doLog(
+	ctx context.Context, level Level, fields Fields, template, message string,
+	values []interface{},
+) {
+	record := recordPool.Get().(*Record)
+	*record = Record{
+		Level:   level,
+		Time:    time.Now(),
+		Prefix:  log.prefix,
+		Message: message,
+		Fields:  log.fields.Merge(fields),
+		Context: ctx,
+	}
+
+	// placeholderCallStackLevel is changed by tests which call logging
+	// functions through helper, it's respected for records too
+	skip := recordCallStackLevel + log.callerSkip +
+		placeholderCallStackLevel - PlaceholderCallStackLevel
+
+	frame := caller(skip)
+
+	record.PC = frame.PC
+	record.File = frame.File
+	record.Line = frame.Line
+	record.Function = frame.Function
+
+	sampler := log.getOptions().sampler
+	if sampler != nil && !sampler.sample(log, record, template) {
+		releaseRecord(record)
+		return
+	}
+
+	record.Stack = log.captureStack(record, values, skip)
+
+	log.redact(record)
+
+	if !log.fireHooks(record) {
+		releaseRecord(record)
 		return
 	}
 
-	log.doLog(level, fmt.Sprintf(format, value...))
+	buffer := bufferPool.Get().(*[]byte)
+
+	// formatter should be called right here, because placeholders use fixed
+	// stack depth for getting information about caller
+	switch format := log.format.(type) {
+	case RecordFormatter:
+		*buffer = append(*buffer, format.RenderRecord(record)...)
+	case *Format:
+		*buffer = format.render(*buffer, record, log.renderOptions())
+	default:
+		*buffer = append(*buffer, log.renderTemplate(
+			format.Render(level, record.Prefix), record,
+		)...)
+	}
+
+	*buffer = append(*buffer, '\n')
+
+	err := log.writeEntry(*buffer, record)
+	if err != nil {
+		log.handleError(fmt.Errorf("failed to write to log: %w", err))
+	}
+
+	putBuffer(buffer)
+	releaseRecord(record)
+}
+
+// writeRecord renders and writes record which has been created not by
+// logging functions of Log, so caller of logging function can't be
+// determined by placeholders using stack depth.
+func (log *Log) writeRecord(record *Record) error {
+	sampler := log.getOptions().sampler
+	if sampler != nil && !sampler.sample(log, record, record.Message) {
+		return nil
+	}
+
+	return log.emitRecord(record)
+}
+
+// emitRecord is the same as writeRecord, but it doesn't sample record.
+func (log *Log) emitRecord(record *Record) error {
+	if record.Stack == nil {
+		record.Stack = log.captureStack(record, nil, -1)
+	}
+
+	log.redact(record)
+
+	if !log.fireHooks(record) {
+		return nil
+	}
+
+	buffer := bufferPool.Get().(*[]byte)
+	defer putBuffer(buffer)
+
+	switch format := log.format.(type) {
+	case RecordFormatter:
+		*buffer = append(*buffer, format.RenderRecord(record)...)
+	case *Format:
+		*buffer = format.render(*buffer, record, log.renderOptions())
+	default:
+		*buffer = append(*buffer, log.renderTemplate(
+			format.Render(record.Level, record.Prefix), record,
+		)...)
+	}
+
+	*buffer = append(*buffer, '\n')
+
+	return log.writeEntry(*buffer, record)
+}
+
+func (log *Log) renderOptions() renderOptions {
+	return renderOptions{
+		colors:      log.colors,
+		shift:       log.shiftIndent,
+		indentLines: log.indentLines,
+	}
 }
 
-func (log *Log) doLog(level Level, value ...interface{}) {
-	format := log.format.Render(level, log.prefix)
+func (log *Log) writeEntry(entry []byte, record *Record) error {
+	log.mutex.Lock()
+	err := log.write(entry, record)
+	log.mutex.Unlock()
+
+	return err
+}
 
-	text := fmt.Sprint(value...)
+func (log *Log) renderTemplate(format string, record *Record) string {
+	text := record.Message
 
 	shift := log.shiftIndent
 	if shift == 0 && log.indentLines {
@@ -36,22 +225,46 @@ func (log *Log) doLog(level Level, value ...interface{}) {
 		text = indent(text, shift)
 	}
 
-	// here is no need for Sprintf, so just replace %s to text
-	entry := strings.Replace(format, "%s", text, 1) + "\n"
+	fieldsText := getFields(record.Fields)
 
-	log.mutex.Lock()
-	err := log.write(entry, level)
-	log.mutex.Unlock()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to write to log: %#v", err)
+	// here is no need for Sprintf, so just replace %s to text, fields are
+	// replaced separately in both parts of format because text or fields can
+	// contain %s or ${fields} too
+	if index := strings.Index(format, "%s"); index >= 0 {
+		return strings.Replace(format[:index], "${fields}", fieldsText, 1) +
+			text +
+			strings.Replace(format[index+2:], "${fields}", fieldsText, 1)
 	}
+
+	return strings.Replace(format, "${fields}", fieldsText, 1)
 }
 
-func (log *Log) write(text string, level Level) error {
-	_, err := log.output.WriteWithLevel([]byte(text), level)
+func (log *Log) write(entry []byte, record *Record) error {
+	if output, ok := log.output.(RecordOutput); ok {
+		_, err := output.WriteRecord(entry, record)
+		return err
+	}
+
+	_, err := log.output.WriteWithLevel(entry, record.Level)
 	return err
 }
 
+func (log *Log) flush() {
+	log.mutex.Lock()
+	output := log.output
+	log.mutex.Unlock()
+
+	flusher, ok := output.(Flusher)
+	if !ok {
+		return
+	}
+
+	err := flusher.Flush()
+	if err != nil {
+		log.handleError(fmt.Errorf("failed to flush log: %w", err))
+	}
+}
+
 func indent(text string, shift int) string {
 	text = strings.Replace(
 		text,
@@ -62,3 +275,29 @@ func indent(text string, shift int) string {
 
 	return text
 }
+
+func releaseRecord(record *Record) {
+	*record = Record{}
+	recordPool.Put(record)
+}
+
+func putBuffer(buffer *[]byte) {
+	if cap(*buffer) > maxPooledBufferSize {
+		return
+	}
+
+	*buffer = (*buffer)[:0]
+	bufferPool.Put(buffer)
+}
+
+// sprint is the same as fmt.Sprint, but it doesn't allocate message if
+// single string is passed.
+func sprint(value []interface{}) string {
+	if len(value) == 1 {
+		if text, ok := value[0].(string); ok {
+			return text
+		}
+	}
+
+	return fmt.Sprint(value...)
+}
@@ -0,0 +1,240 @@
+// Package lorgtest provides logger which captures log records in memory, so
+// tests can check logged records without parsing formatted output.
+//
+//	logger := lorgtest.New()
+//	service := NewService(logger.Log)
+//	service.Run()
+//
+//	logger.AssertLogged(t, lorg.LevelError, "connection refused")
+package lorgtest
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kovetskiy/lorg"
+)
+
+// Formatting is the format which is used for Entry.Text and for mirroring
+// records to testing log.
+const Formatting = `${level:[%s]} ${prefix}%s${fields}`
+
+// Entry is a log record captured by Logger.
+type Entry struct {
+	Level   lorg.Level
+	Time    time.Time
+	Prefix  string
+	Message string
+	Fields  lorg.Fields
+
+	// File, Line and Function describe the place where logging function
+	// has been called.
+	File     string
+	Line     int
+	Function string
+
+	// Text is the record formatted using Formatting without trailing
+	// newline.
+	Text string
+}
+
+// String returns text of given entry.
+func (entry Entry) String() string {
+	return entry.Text
+}
+
+// Logger is the lorg.Log which captures records of all levels instead of
+// writing them, children of Logger created by With, WithFields or NewChild
+// write records to the same Logger.
+//
+// Fatal and Fatalf of Logger and it's children don't exit, exit code is
+// captured and can be obtained by ExitCode.
+//
+// Do not instantiate Logger instance without using New.
+type Logger struct {
+	*lorg.Log
+
+	entries  []Entry
+	mirror   testing.TB
+	exitCode int
+	exited   bool
+	mutex    *sync.Mutex
+}
+
+// New creates Logger instance with LevelTrace level.
+func New() *Logger {
+	logger := &Logger{
+		Log:   lorg.NewLog(),
+		mutex: &sync.Mutex{},
+	}
+
+	logger.SetLevel(lorg.LevelTrace)
+	logger.SetFormat(lorg.NewFormat(Formatting))
+	logger.SetOutput(&output{logger: logger})
+	logger.SetExiter(logger.exit)
+
+	return logger
+}
+
+// SetMirror forces Logger to write captured records to log of given test
+// using t.Log, so records are interleaved with test output. Records must not
+// be logged after the test has been completed.
+func (logger *Logger) SetMirror(t testing.TB) *Logger {
+	logger.mutex.Lock()
+	logger.mirror = t
+	logger.mutex.Unlock()
+
+	return logger
+}
+
+// Entries returns copy of captured entries in order of logging.
+func (logger *Logger) Entries() []Entry {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+
+	return append([]Entry(nil), logger.entries...)
+}
+
+// Find returns captured entries with given level which messages contain given
+// substring.
+func (logger *Logger) Find(level lorg.Level, substring string) []Entry {
+	var found []Entry
+	for _, entry := range logger.Entries() {
+		if entry.Level == level && strings.Contains(entry.Message, substring) {
+			found = append(found, entry)
+		}
+	}
+
+	return found
+}
+
+// Reset removes captured entries and exit code.
+func (logger *Logger) Reset() {
+	logger.mutex.Lock()
+	logger.entries = nil
+	logger.exitCode = 0
+	logger.exited = false
+	logger.mutex.Unlock()
+}
+
+// ExitCode returns code which has been passed to exiter by Fatal or Fatalf,
+// exited is false if exiter has not been called.
+func (logger *Logger) ExitCode() (code int, exited bool) {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+
+	return logger.exitCode, logger.exited
+}
+
+// AssertLogged checks that record with given level which message contains
+// given substring has been logged, test is marked as failed otherwise.
+func (logger *Logger) AssertLogged(
+	t testing.TB, level lorg.Level, substring string,
+) bool {
+	t.Helper()
+
+	if len(logger.Find(level, substring)) != 0 {
+		return true
+	}
+
+	t.Errorf(
+		"expected %s record containing %q, got:\n%s",
+		level, substring, logger.dump(),
+	)
+
+	return false
+}
+
+// AssertNotLogged checks that record with given level which message
+// contains given substring has not been logged, test is marked as failed
+// otherwise.
+func (logger *Logger) AssertNotLogged(
+	t testing.TB, level lorg.Level, substring string,
+) bool {
+	t.Helper()
+
+	found := logger.Find(level, substring)
+	if len(found) == 0 {
+		return true
+	}
+
+	t.Errorf(
+		"unexpected %s record containing %q: %s",
+		level, substring, found[0],
+	)
+
+	return false
+}
+
+func (logger *Logger) exit(code int) {
+	logger.mutex.Lock()
+	logger.exitCode = code
+	logger.exited = true
+	logger.mutex.Unlock()
+}
+
+func (logger *Logger) dump() string {
+	entries := logger.Entries()
+	if len(entries) == 0 {
+		return "(no records)"
+	}
+
+	lines := make([]string, len(entries))
+	for index, entry := range entries {
+		lines[index] = fmt.Sprintf(
+			"%s:%d: %s", filepath.Base(entry.File), entry.Line, entry.Text,
+		)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// output captures records written by Log into Logger.
+type output struct {
+	logger *Logger
+}
+
+func (output *output) Write(data []byte) (int, error) {
+	return output.WriteRecord(data, &lorg.Record{Level: lorg.LevelInfo})
+}
+
+func (output *output) WriteWithLevel(
+	data []byte, level lorg.Level,
+) (int, error) {
+	return output.WriteRecord(data, &lorg.Record{Level: level})
+}
+
+func (output *output) WriteRecord(
+	data []byte, record *lorg.Record,
+) (int, error) {
+	// record is reused by Log, so it's copied into entry
+	entry := Entry{
+		Level:    record.Level,
+		Time:     record.Time,
+		Prefix:   record.Prefix,
+		Message:  record.Message,
+		Fields:   record.Fields,
+		File:     record.File,
+		Line:     record.Line,
+		Function: record.Function,
+		Text:     string(bytes.TrimRight(data, "\n")),
+	}
+
+	logger := output.logger
+
+	logger.mutex.Lock()
+	logger.entries = append(logger.entries, entry)
+	mirror := logger.mirror
+	logger.mutex.Unlock()
+
+	if mirror != nil {
+		mirror.Log(entry.Text)
+	}
+
+	return len(data), nil
+}
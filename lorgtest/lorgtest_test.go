@@ -0,0 +1,113 @@
+package lorgtest
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/kovetskiy/lorg"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder is testing.TB which records errors and logs instead of reporting
+// them.
+type recorder struct {
+	testing.TB
+	errors []string
+	logs   []string
+}
+
+func (recorder *recorder) Helper() {}
+
+func (recorder *recorder) Errorf(format string, value ...interface{}) {
+	recorder.errors = append(recorder.errors, fmt.Sprintf(format, value...))
+}
+
+func (recorder *recorder) Log(value ...interface{}) {
+	recorder.logs = append(recorder.logs, fmt.Sprint(value...))
+}
+
+func TestLogger_Entries_ReturnsCapturedRecords(t *testing.T) {
+	test := assert.New(t)
+
+	logger := New()
+	logger.SetPrefix("app")
+
+	logger.Debug("starting")
+	logger.With("port", 80).Errorf("can't listen %s", "tcp")
+
+	entries := logger.Entries()
+	test.Len(entries, 2)
+
+	test.Equal(lorg.LevelDebug, entries[0].Level)
+	test.Equal("starting", entries[0].Message)
+	test.Equal("app", entries[0].Prefix)
+	test.Equal("[DEBUG] app starting", entries[0].Text)
+
+	test.Equal(lorg.LevelError, entries[1].Level)
+	test.Equal("can't listen tcp", entries[1].Message)
+	test.Equal(lorg.Fields{{Key: "port", Value: 80}}, entries[1].Fields)
+	test.Equal("lorgtest_test.go", filepath.Base(entries[1].File))
+	test.Contains(entries[1].Function, "TestLogger_Entries")
+	test.False(entries[1].Time.IsZero())
+
+	logger.Reset()
+	test.Empty(logger.Entries())
+}
+
+func TestLogger_AssertLogged_ReportsMissingRecords(t *testing.T) {
+	test := assert.New(t)
+
+	logger := New()
+	logger.Warning("disk is almost full")
+
+	fake := &recorder{TB: t}
+
+	test.True(logger.AssertLogged(fake, lorg.LevelWarning, "almost full"))
+	test.False(logger.AssertLogged(fake, lorg.LevelError, "almost full"))
+	test.True(logger.AssertNotLogged(fake, lorg.LevelError, "full"))
+	test.False(logger.AssertNotLogged(fake, lorg.LevelWarning, "disk"))
+
+	test.Len(fake.errors, 2)
+	test.Contains(fake.errors[0], `expected ERROR record containing "almost`)
+	test.Contains(fake.errors[0], "[WARNING] disk is almost full")
+	test.Contains(fake.errors[1], `unexpected WARNING record`)
+}
+
+func TestLogger_Fatal_CapturesExitCode(t *testing.T) {
+	test := assert.New(t)
+
+	logger := New()
+
+	_, exited := logger.ExitCode()
+	test.False(exited)
+
+	logger.With("a", 1).Fatal("broken")
+
+	code, exited := logger.ExitCode()
+	test.True(exited)
+	test.Equal(1, code)
+
+	logger.AssertLogged(t, lorg.LevelFatal, "broken")
+
+	logger.Reset()
+	logger.NewChildWithPrefix("child").Fatalf("broken child")
+
+	code, exited = logger.ExitCode()
+	test.True(exited)
+	test.Equal(1, code)
+
+	logger.AssertLogged(t, lorg.LevelFatal, "broken child")
+}
+
+func TestLogger_SetMirror_WritesRecordsToTestLog(t *testing.T) {
+	test := assert.New(t)
+
+	fake := &recorder{TB: t}
+
+	logger := New().SetMirror(fake)
+	logger.Info("one")
+	logger.Trace("two")
+
+	test.Equal([]string{"[INFO] one", "[TRACE] two"}, fake.logs)
+}
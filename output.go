@@ -1,19 +1,80 @@
 package lorg
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"reflect"
+	"strings"
 	"sync"
+	"time"
 )
 
+// ErrWriterDisabled is returned by Output if a record has not been written
+// because all it's writers are disabled by retry policy, see
+// Output.SetRetryPolicy.
+var ErrWriterDisabled = errors.New("writer is disabled after failures")
+
 type SmartOutput interface {
 	io.Writer
 	WriteWithLevel([]byte, Level) (int, error)
 }
 
+// Flusher is the interface which should be implemented by outputs which
+// buffer log records, Log flushes output before calling exiter in Fatal and
+// Fatalf.
+type Flusher interface {
+	Flush() error
+}
+
+// RecordOutput is the interface which can be implemented by SmartOutput if
+// it needs the whole log record for choosing writers or writing, Log will
+// use WriteRecord instead of WriteWithLevel for such outputs.
+//
+// Data and record are reused by Log after WriteRecord returns, so they should
+// be copied if output retains them.
+type RecordOutput interface {
+	SmartOutput
+	WriteRecord([]byte, *Record) (int, error)
+}
+
+// Condition reports whether given record should be written to writers of the
+// route, see Output.Route.
+type Condition func(record *Record) bool
+
+// ensure that Output implements RecordOutput and Flusher interfaces.
+var (
+	_ RecordOutput = (*Output)(nil)
+	_ Flusher      = (*Output)(nil)
+)
+
 type Output struct {
 	conditions map[Level][]io.Writer
+	routes     []route
+	fallbacks  []io.Writer
 	mutex      *sync.Mutex
+
+	errorHandler func(error)
+
+	failures    map[io.Writer]*writerFailures
+	maxFailures int
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+
+	// for test purposes
+	now func() time.Time
+}
+
+type writerFailures struct {
+	count         int
+	backoff       time.Duration
+	disabledUntil time.Time
+}
+
+type route struct {
+	condition Condition
+	writers   []io.Writer
+	only      bool
 }
 
 func NewOutput(stderr io.Writer) *Output {
@@ -26,7 +87,9 @@ func NewOutput(stderr io.Writer) *Output {
 			LevelDebug:   {stderr},
 			LevelTrace:   {stderr},
 		},
-		mutex: &sync.Mutex{},
+		mutex:    &sync.Mutex{},
+		failures: map[io.Writer]*writerFailures{},
+		now:      time.Now,
 	}
 }
 
@@ -42,28 +105,423 @@ func (output *Output) SetLevelWriterCondition(
 	return output
 }
 
+// SetErrorHandler sets function which will be called with errors of writers
+// instead of returning them from WriteWithLevel.
+func (output *Output) SetErrorHandler(handler func(error)) *Output {
+	output.mutex.Lock()
+
+	output.errorHandler = handler
+
+	output.mutex.Unlock()
+
+	return output
+}
+
+// SetFallbackWriter sets chain of writers which will be used if any writer
+// of a record fails or is disabled, writers of the chain are tried in order
+// until one of them succeeds.
+func (output *Output) SetFallbackWriter(writer ...io.Writer) *Output {
+	output.mutex.Lock()
+
+	output.fallbacks = writer
+
+	output.mutex.Unlock()
+
+	return output
+}
+
+// SetRetryPolicy sets amount of consecutive failures after which a writer
+// will be disabled, disabled writer is retried after backoff period which
+// starts from minBackoff and doubles after every failed retry up to
+// maxBackoff. Successful write resets writer failures.
+//
+// Writers are never disabled if retry policy is not set or maxFailures is
+// zero. If all writers of a record including fallback writers are disabled,
+// ErrWriterDisabled is returned, so records are not lost silently.
+func (output *Output) SetRetryPolicy(
+	maxFailures int, minBackoff, maxBackoff time.Duration,
+) *Output {
+	output.mutex.Lock()
+
+	output.maxFailures = maxFailures
+	output.minBackoff = minBackoff
+	output.maxBackoff = maxBackoff
+
+	output.mutex.Unlock()
+
+	return output
+}
+
 func (output *Output) Write(buffer []byte) (int, error) {
 	panic("should be not called")
 }
 
+// Route adds route which writes records matching given condition to given
+// writers in addition to writers of level conditions and other matching
+// routes, every writer receives a record only once even if it's used by
+// several matching routes:
+//
+//	output.Route(MinLevel(LevelWarning), errorsFile)
+func (output *Output) Route(
+	condition Condition, writer ...io.Writer,
+) *Output {
+	output.mutex.Lock()
+
+	output.routes = append(output.routes, route{
+		condition: condition,
+		writers:   writer,
+	})
+
+	output.mutex.Unlock()
+
+	return output
+}
+
+// RouteOnly adds route which writes records matching given condition only to
+// given writers, writers of level conditions and other routes are ignored
+// for such records. Routes are checked in order of adding, so the first
+// matching RouteOnly route wins.
+func (output *Output) RouteOnly(
+	condition Condition, writer ...io.Writer,
+) *Output {
+	output.mutex.Lock()
+
+	output.routes = append(output.routes, route{
+		condition: condition,
+		writers:   writer,
+		only:      true,
+	})
+
+	output.mutex.Unlock()
+
+	return output
+}
+
 func (output *Output) WriteWithLevel(
 	data []byte, level Level,
+) (int, error) {
+	return output.WriteRecord(data, &Record{Level: level})
+}
+
+// WriteRecord writes given data to writers of level condition of record
+// level and to writers of routes matching given record.
+func (output *Output) WriteRecord(
+	data []byte, record *Record,
 ) (int, error) {
 	output.mutex.Lock()
 
-	writers, ok := output.conditions[level]
+	writers, ok := output.getWriters(record)
 	if !ok {
 		output.mutex.Unlock()
-		return 0, fmt.Errorf("there is no writers for level %s", level)
+		return 0, fmt.Errorf(
+			"there is no writers for level %s", record.Level,
+		)
 	}
 
-	var written int
-	var err error
+	var (
+		written  int
+		failed   bool
+		accepted bool
+		disabled bool
+		errs     []error
+	)
+
 	for _, writer := range writers {
-		written, err = writer.Write(data)
+		count, skipped, err := output.write(writer, data)
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		if err != nil || skipped {
+			failed = true
+			disabled = disabled || skipped
+		} else {
+			written = count
+			accepted = true
+		}
+	}
+
+	if failed && len(output.fallbacks) != 0 {
+		for _, writer := range output.fallbacks {
+			count, skipped, err := output.write(writer, data)
+			if err != nil {
+				errs = append(errs, err)
+			}
+
+			if err == nil && !skipped {
+				written = count
+				accepted = true
+				break
+			}
+
+			disabled = disabled || skipped
+		}
 	}
 
+	if !accepted && disabled && len(errs) == 0 {
+		errs = append(errs, ErrWriterDisabled)
+	}
+
+	err := errors.Join(errs...)
+
+	handler := output.errorHandler
+
 	output.mutex.Unlock()
 
+	if err != nil && handler != nil {
+		handler(err)
+		return written, nil
+	}
+
 	return written, err
 }
+
+// Flush flushes all writers of level conditions, routes and fallback writers
+// which implement Flusher, so records buffered by writers like AsyncOutput
+// are written before Fatal calls exiter. Errors are passed to error handler
+// if it's set.
+func (output *Output) Flush() error {
+	output.mutex.Lock()
+
+	var writers []io.Writer
+	for level := LevelFatal; level <= LevelTrace; level++ {
+		writers = append(writers, output.conditions[level]...)
+	}
+
+	for _, route := range output.routes {
+		writers = append(writers, route.writers...)
+	}
+
+	writers = append(writers, output.fallbacks...)
+
+	handler := output.errorHandler
+
+	output.mutex.Unlock()
+
+	var errs []error
+	for _, writer := range uniqueWriters(writers) {
+		flusher, ok := writer.(Flusher)
+		if !ok {
+			continue
+		}
+
+		err := flusher.Flush()
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	err := errors.Join(errs...)
+	if err != nil && handler != nil {
+		handler(err)
+		return nil
+	}
+
+	return err
+}
+
+// write writes data to given writer if it's not disabled and tracks writer
+// failures, skipped is true if writer is disabled.
+func (output *Output) write(
+	writer io.Writer, data []byte,
+) (written int, skipped bool, err error) {
+	// writers which can't be used as map keys are never disabled
+	trackable := reflect.TypeOf(writer).Comparable()
+
+	var failures *writerFailures
+	if trackable {
+		failures = output.failures[writer]
+		if failures != nil && output.now().Before(failures.disabledUntil) {
+			return 0, true, nil
+		}
+	}
+
+	written, err = writer.Write(data)
+	if !trackable {
+		return written, false, err
+	}
+
+	if err == nil {
+		if failures != nil {
+			delete(output.failures, writer)
+		}
+
+		return written, false, nil
+	}
+
+	if failures == nil {
+		failures = &writerFailures{}
+		output.failures[writer] = failures
+	}
+
+	failures.count++
+	if output.maxFailures > 0 && failures.count >= output.maxFailures {
+		if failures.backoff == 0 {
+			failures.backoff = output.minBackoff
+		} else {
+			failures.backoff *= 2
+		}
+
+		if failures.backoff > output.maxBackoff {
+			failures.backoff = output.maxBackoff
+		}
+
+		failures.disabledUntil = output.now().Add(failures.backoff)
+
+		err = fmt.Errorf(
+			"writer %T is disabled for %s: %w", writer, failures.backoff, err,
+		)
+	}
+
+	return written, false, err
+}
+
+func (output *Output) getWriters(record *Record) ([]io.Writer, bool) {
+	var routed []io.Writer
+	for _, route := range output.routes {
+		if !route.condition(record) {
+			continue
+		}
+
+		if route.only {
+			return uniqueWriters(route.writers), true
+		}
+
+		routed = append(routed, route.writers...)
+	}
+
+	writers, ok := output.conditions[record.Level]
+	if !ok && len(routed) == 0 {
+		return nil, false
+	}
+
+	if len(routed) == 0 {
+		return writers, true
+	}
+
+	return uniqueWriters(append(append([]io.Writer{}, writers...), routed...)),
+		true
+}
+
+func uniqueWriters(writers []io.Writer) []io.Writer {
+	unique := make([]io.Writer, 0, len(writers))
+
+	for _, writer := range writers {
+		found := false
+		if reflect.TypeOf(writer).Comparable() {
+			for _, existing := range unique {
+				if existing == writer {
+					found = true
+					break
+				}
+			}
+		}
+
+		if !found {
+			unique = append(unique, writer)
+		}
+	}
+
+	return unique
+}
+
+// MinLevel returns Condition which matches records with given level or
+// more severe levels, MinLevel(LevelWarning) matches LevelWarning,
+// LevelError and LevelFatal.
+func MinLevel(level Level) Condition {
+	return func(record *Record) bool {
+		return record.Level <= level
+	}
+}
+
+// MaxLevel returns Condition which matches records with given level or less
+// severe levels, MaxLevel(LevelDebug) matches LevelDebug and LevelTrace.
+func MaxLevel(level Level) Condition {
+	return func(record *Record) bool {
+		return record.Level >= level
+	}
+}
+
+// Between returns Condition which matches records with levels between given
+// levels inclusively, levels can be passed in any order.
+func Between(from, to Level) Condition {
+	if from > to {
+		from, to = to, from
+	}
+
+	return func(record *Record) bool {
+		return record.Level >= from && record.Level <= to
+	}
+}
+
+// PrefixIs returns Condition which matches records of loggers with given
+// prefix.
+func PrefixIs(prefix string) Condition {
+	return func(record *Record) bool {
+		return record.Prefix == prefix
+	}
+}
+
+// PrefixHas returns Condition which matches records of loggers which prefix
+// starts with given string.
+func PrefixHas(prefix string) Condition {
+	return func(record *Record) bool {
+		return strings.HasPrefix(record.Prefix, prefix)
+	}
+}
+
+// FieldIs returns Condition which matches records with field of given key
+// and value, values are compared using fmt.Sprint.
+func FieldIs(key string, value interface{}) Condition {
+	expected := fmt.Sprint(value)
+
+	return func(record *Record) bool {
+		for _, field := range record.Fields {
+			if field.Key == key {
+				return fmt.Sprint(field.Value) == expected
+			}
+		}
+
+		return false
+	}
+}
+
+// HasField returns Condition which matches records with field of given key.
+func HasField(key string) Condition {
+	return func(record *Record) bool {
+		for _, field := range record.Fields {
+			if field.Key == key {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// All returns Condition which matches records matching all given conditions.
+func All(conditions ...Condition) Condition {
+	return func(record *Record) bool {
+		for _, condition := range conditions {
+			if !condition(record) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// Any returns Condition which matches records matching any of given
+// conditions.
+func Any(conditions ...Condition) Condition {
+	return func(record *Record) bool {
+		for _, condition := range conditions {
+			if condition(record) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
@@ -0,0 +1,274 @@
+package lorg
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// DropPolicy describes what AsyncOutput does with records when queue is full.
+type DropPolicy int
+
+const (
+	// DropPolicyBlock blocks logging function until queue has free space.
+	DropPolicyBlock DropPolicy = iota
+
+	// DropPolicyNewest drops the record which is being written.
+	DropPolicyNewest
+
+	// DropPolicyOldest drops the oldest record in the queue.
+	DropPolicyOldest
+
+	// DropPolicyBelowLevel drops the record which is being written if it's
+	// level is less severe than level passed to AsyncOutput.SetDropLevel,
+	// otherwise blocks logging function like DropPolicyBlock.
+	DropPolicyBelowLevel
+)
+
+// ErrOutputClosed is returned by outputs which has been closed.
+var ErrOutputClosed = errors.New("output is closed")
+
+// ensure that AsyncOutput implements RecordOutput and Flusher interfaces.
+var (
+	_ RecordOutput = (*AsyncOutput)(nil)
+	_ Flusher      = (*AsyncOutput)(nil)
+)
+
+// AsyncOutput is the SmartOutput which puts log records into a bounded queue
+// and writes them to the underlying output in background, so slow output
+// doesn't block logging functions.
+//
+// Errors of the underlying output are returned by Flush.
+//
+// Do not instantiate AsyncOutput instance without using NewAsyncOutput.
+type AsyncOutput struct {
+	output    SmartOutput
+	queue     chan asyncRecord
+	policy    DropPolicy
+	dropLevel Level
+	dropped   uint64
+
+	pending      int
+	err          error
+	pendingMutex *sync.Mutex
+	flushed      *sync.Cond
+
+	closed     bool
+	closeMutex *sync.RWMutex
+	done       chan struct{}
+}
+
+type asyncRecord struct {
+	data   []byte
+	record *Record
+}
+
+// NewAsyncOutput creates AsyncOutput with queue of given size which writes
+// records to given output, if output doesn't implement SmartOutput it will
+// be wrapped using NewOutput.
+//
+// Default drop policy is DropPolicyBlock.
+func NewAsyncOutput(output io.Writer, size int) *AsyncOutput {
+	smart, ok := output.(SmartOutput)
+	if !ok {
+		smart = NewOutput(output)
+	}
+
+	async := &AsyncOutput{
+		output:       smart,
+		queue:        make(chan asyncRecord, size),
+		policy:       DropPolicyBlock,
+		dropLevel:    LevelInfo,
+		pendingMutex: &sync.Mutex{},
+		closeMutex:   &sync.RWMutex{},
+		done:         make(chan struct{}),
+	}
+
+	async.flushed = sync.NewCond(async.pendingMutex)
+
+	go async.work()
+
+	return async
+}
+
+// SetDropPolicy sets policy which will be used when queue is full.
+func (async *AsyncOutput) SetDropPolicy(policy DropPolicy) *AsyncOutput {
+	async.closeMutex.Lock()
+	async.policy = policy
+	async.closeMutex.Unlock()
+
+	return async
+}
+
+// SetDropLevel sets level for DropPolicyBelowLevel, records with less severe
+// levels will be dropped when queue is full.
+func (async *AsyncOutput) SetDropLevel(level Level) *AsyncOutput {
+	async.closeMutex.Lock()
+	async.dropLevel = level
+	async.closeMutex.Unlock()
+
+	return async
+}
+
+// Dropped returns amount of records which has been dropped because queue
+// was full.
+func (async *AsyncOutput) Dropped() uint64 {
+	return atomic.LoadUint64(&async.dropped)
+}
+
+// Write puts given data into the queue with LevelInfo level.
+func (async *AsyncOutput) Write(data []byte) (int, error) {
+	return async.WriteWithLevel(data, LevelInfo)
+}
+
+// WriteWithLevel puts copy of given data into the queue or drops it according
+// to drop policy if queue is full.
+func (async *AsyncOutput) WriteWithLevel(
+	data []byte, level Level,
+) (int, error) {
+	return async.WriteRecord(data, &Record{Level: level})
+}
+
+// WriteRecord is the same as WriteWithLevel, but given record will be passed
+// to the underlying output if it implements RecordOutput.
+func (async *AsyncOutput) WriteRecord(
+	data []byte, record *Record,
+) (int, error) {
+	async.closeMutex.RLock()
+	defer async.closeMutex.RUnlock()
+
+	if async.closed {
+		return 0, ErrOutputClosed
+	}
+
+	// record is reused by Log after WriteRecord returns, so it's copied
+	copied := *record
+
+	queued := asyncRecord{
+		data:   append([]byte(nil), data...),
+		record: &copied,
+	}
+
+	async.addPending(1)
+
+	switch async.policy {
+	case DropPolicyNewest:
+		async.enqueueOrDrop(queued)
+
+	case DropPolicyOldest:
+		async.enqueueDroppingOldest(queued)
+
+	case DropPolicyBelowLevel:
+		if record.Level > async.dropLevel {
+			async.enqueueOrDrop(queued)
+		} else {
+			async.queue <- queued
+		}
+
+	default:
+		async.queue <- queued
+	}
+
+	return len(data), nil
+}
+
+// Flush waits until all queued records are written to the underlying output
+// and returns errors which occurred since previous Flush.
+func (async *AsyncOutput) Flush() error {
+	async.pendingMutex.Lock()
+
+	for async.pending > 0 {
+		async.flushed.Wait()
+	}
+
+	err := async.err
+	async.err = nil
+
+	async.pendingMutex.Unlock()
+
+	return err
+}
+
+// Close flushes queued records and stops background writing, all following
+// writes will return ErrOutputClosed.
+func (async *AsyncOutput) Close() error {
+	async.closeMutex.Lock()
+	if async.closed {
+		async.closeMutex.Unlock()
+		return nil
+	}
+
+	async.closed = true
+	close(async.queue)
+	async.closeMutex.Unlock()
+
+	<-async.done
+
+	return async.Flush()
+}
+
+func (async *AsyncOutput) enqueueOrDrop(record asyncRecord) {
+	select {
+	case async.queue <- record:
+	default:
+		async.drop()
+	}
+}
+
+func (async *AsyncOutput) enqueueDroppingOldest(record asyncRecord) {
+	for {
+		select {
+		case async.queue <- record:
+			return
+		default:
+		}
+
+		select {
+		case <-async.queue:
+			async.drop()
+		default:
+		}
+	}
+}
+
+func (async *AsyncOutput) drop() {
+	atomic.AddUint64(&async.dropped, 1)
+	async.addPending(-1)
+}
+
+func (async *AsyncOutput) work() {
+	recordOutput, isRecordOutput := async.output.(RecordOutput)
+
+	for queued := range async.queue {
+		var err error
+		if isRecordOutput {
+			_, err = recordOutput.WriteRecord(queued.data, queued.record)
+		} else {
+			_, err = async.output.WriteWithLevel(
+				queued.data, queued.record.Level,
+			)
+		}
+
+		async.pendingMutex.Lock()
+		if err != nil {
+			async.err = errors.Join(async.err, err)
+		}
+		async.pendingMutex.Unlock()
+
+		async.addPending(-1)
+	}
+
+	close(async.done)
+}
+
+func (async *AsyncOutput) addPending(delta int) {
+	async.pendingMutex.Lock()
+
+	async.pending += delta
+	if async.pending == 0 {
+		async.flushed.Broadcast()
+	}
+
+	async.pendingMutex.Unlock()
+}
@@ -0,0 +1,128 @@
+package lorg
+
+import (
+	"bytes"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// gatedWriter blocks every write until gate is opened.
+type gatedWriter struct {
+	gate   chan struct{}
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (writer *gatedWriter) Write(data []byte) (int, error) {
+	<-writer.gate
+
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+
+	return writer.buffer.Write(data)
+}
+
+func (writer *gatedWriter) String() string {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+
+	return writer.buffer.String()
+}
+
+func TestAsyncOutput_DropPolicies(t *testing.T) {
+	test := assert.New(t)
+
+	testcases := []struct {
+		policy   DropPolicy
+		expected string
+		dropped  uint64
+	}{
+		{DropPolicyNewest, "1\n2\n3\n", 2},
+		{DropPolicyOldest, "1\n4\n5\n", 2},
+		{DropPolicyBelowLevel, "1\n2\n3\n", 2},
+	}
+
+	for _, testcase := range testcases {
+		writer := &gatedWriter{gate: make(chan struct{})}
+
+		async := NewAsyncOutput(writer, 2).SetDropPolicy(testcase.policy)
+		async.SetDropLevel(LevelWarning)
+
+		// first record is taken by worker which is blocked on the gate.
+		_, err := async.WriteWithLevel([]byte("1\n"), LevelInfo)
+		test.NoError(err)
+		for len(async.queue) != 0 {
+			runtime.Gosched()
+		}
+
+		for _, text := range []string{"2\n", "3\n", "4\n", "5\n"} {
+			_, err := async.WriteWithLevel([]byte(text), LevelInfo)
+			test.NoError(err)
+		}
+
+		close(writer.gate)
+
+		test.NoError(async.Close())
+		test.Equal(testcase.expected, writer.String(), "%d", testcase.policy)
+		test.Equal(testcase.dropped, async.Dropped(), "%d", testcase.policy)
+
+		_, err = async.WriteWithLevel([]byte("6\n"), LevelInfo)
+		test.Equal(ErrOutputClosed, err)
+	}
+}
+
+func TestLog_Fatal_FlushesAsyncOutput(t *testing.T) {
+	test := assert.New(t)
+
+	writer := &gatedWriter{gate: make(chan struct{})}
+	close(writer.gate)
+
+	log := NewLog()
+	log.SetOutput(NewAsyncOutput(writer, 10))
+	log.SetFormat(NewFormat(`${level} %s`))
+
+	var written string
+	log.SetExiter(func(int) {
+		written = writer.String()
+	})
+
+	log.Info("1")
+	log.Fatal("2")
+
+	test.Equal("INFO 1\nFATAL 2\n", written)
+}
+
+func TestLog_Fatal_FlushesAsyncWritersOfOutput(t *testing.T) {
+	test := assert.New(t)
+
+	writer := &gatedWriter{gate: make(chan struct{})}
+	close(writer.gate)
+
+	routed := &gatedWriter{gate: make(chan struct{})}
+	close(routed.gate)
+
+	var buffer bytes.Buffer
+
+	log := NewLog()
+	log.SetOutput(
+		NewOutput(NewAsyncOutput(writer, 10)).
+			Route(MinLevel(LevelFatal), NewAsyncOutput(routed, 10), &buffer),
+	)
+	log.SetFormat(NewFormat(`${level} %s`))
+
+	var written, routedWritten string
+	log.SetExiter(func(int) {
+		written = writer.String()
+		routedWritten = routed.String()
+	})
+
+	log.Info("1")
+	log.Fatal("2")
+
+	test.Equal("INFO 1\nFATAL 2\n", written)
+	test.Equal("FATAL 2\n", routedWritten)
+	test.Equal("FATAL 2\n", buffer.String())
+}
@@ -0,0 +1,215 @@
+package lorg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// JournalSocket is the path to the native protocol socket of
+	// systemd-journald.
+	JournalSocket = "/run/systemd/journal/socket"
+
+	// journalMaxFieldKeyLength is the maximum length of field names which
+	// are accepted by journald.
+	journalMaxFieldKeyLength = 64
+)
+
+// ensure that JournalOutput implements RecordOutput interface.
+var _ RecordOutput = (*JournalOutput)(nil)
+
+// JournalOutput is the RecordOutput which sends log records to
+// systemd-journald using the native journal protocol, logging levels are
+// mapped to PRIORITY field like in SyslogOutput.
+//
+// Besides MESSAGE and PRIORITY, JournalOutput sends CODE_FILE, CODE_LINE and
+// CODE_FUNC fields describing caller, SYSLOG_IDENTIFIER field which is the
+// logger prefix or identifier of the output if prefix is empty, and
+// structured fields of the record with keys converted to upper case. Fields
+// with the same names as fields sent by JournalOutput are prefixed by FIELD_:
+// FIELD_PRIORITY.
+//
+// MESSAGE is the record formatted by Log format, so format without time,
+// level and prefix placeholders should be used: `%s`.
+//
+// Entries which are too large for a datagram are passed to journald using a
+// sealed memfd (or unlinked temporary file) on Linux.
+//
+// Do not instantiate JournalOutput instance without using NewJournalOutput.
+type JournalOutput struct {
+	address    *net.UnixAddr
+	conn       *net.UnixConn
+	identifier string
+	mutex      *sync.Mutex
+}
+
+// NewJournalOutput creates JournalOutput which sends entries to the journal
+// socket with given path or to JournalSocket if path is empty. Default
+// identifier is the base name of the executable.
+func NewJournalOutput(path string) (*JournalOutput, error) {
+	if path == "" {
+		path = JournalSocket
+	}
+
+	// unbound socket is used, so entries can be sent to the journal socket
+	// even if journald has been restarted.
+	conn, err := net.ListenUnixgram(
+		"unixgram", &net.UnixAddr{Net: "unixgram"},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JournalOutput{
+		address:    &net.UnixAddr{Name: path, Net: "unixgram"},
+		conn:       conn,
+		identifier: filepath.Base(os.Args[0]),
+		mutex:      &sync.Mutex{},
+	}, nil
+}
+
+// SetIdentifier sets SYSLOG_IDENTIFIER which will be used for records of
+// loggers without prefix.
+func (journal *JournalOutput) SetIdentifier(
+	identifier string,
+) *JournalOutput {
+	journal.mutex.Lock()
+	journal.identifier = identifier
+	journal.mutex.Unlock()
+
+	return journal
+}
+
+// Write sends given data with LevelInfo level.
+func (journal *JournalOutput) Write(data []byte) (int, error) {
+	return journal.WriteWithLevel(data, LevelInfo)
+}
+
+// WriteWithLevel sends given data with priority which corresponds to given
+// level.
+func (journal *JournalOutput) WriteWithLevel(
+	data []byte, level Level,
+) (int, error) {
+	return journal.WriteRecord(data, &Record{Level: level})
+}
+
+// WriteRecord sends given data as MESSAGE with fields of given record.
+func (journal *JournalOutput) WriteRecord(
+	data []byte, record *Record,
+) (int, error) {
+	journal.mutex.Lock()
+	defer journal.mutex.Unlock()
+
+	entry := &bytes.Buffer{}
+
+	writeJournalField(entry, "MESSAGE", string(bytes.TrimRight(data, "\n")))
+	writeJournalField(
+		entry, "PRIORITY", strconv.Itoa(syslogSeverity(record.Level)),
+	)
+
+	identifier := record.Prefix
+	if identifier == "" {
+		identifier = journal.identifier
+	}
+
+	writeJournalField(entry, "SYSLOG_IDENTIFIER", identifier)
+
+	if record.File != "" {
+		writeJournalField(entry, "CODE_FILE", record.File)
+		writeJournalField(entry, "CODE_LINE", strconv.Itoa(record.Line))
+	}
+
+	if record.Function != "" {
+		writeJournalField(entry, "CODE_FUNC", record.Function)
+	}
+
+	for _, field := range record.Fields {
+		key := journalFieldKey(field.Key)
+		if key == "" {
+			continue
+		}
+
+		writeJournalField(entry, key, formatJournalValue(field.Value))
+	}
+
+	_, _, err := journal.conn.WriteMsgUnix(
+		entry.Bytes(), nil, journal.address,
+	)
+	if err != nil && isJournalEntryTooLarge(err) {
+		err = sendJournalFile(journal.conn, journal.address, entry.Bytes())
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return len(data), nil
+}
+
+// Close closes the socket.
+func (journal *JournalOutput) Close() error {
+	return journal.conn.Close()
+}
+
+func writeJournalField(buffer *bytes.Buffer, key string, value string) {
+	buffer.WriteString(key)
+
+	if strings.ContainsRune(value, '\n') {
+		buffer.WriteByte('\n')
+
+		size := make([]byte, 8)
+		binary.LittleEndian.PutUint64(size, uint64(len(value)))
+		buffer.Write(size)
+	} else {
+		buffer.WriteByte('=')
+	}
+
+	buffer.WriteString(value)
+	buffer.WriteByte('\n')
+}
+
+// journalFieldKey converts given key to the journal field name which can
+// contain only upper case letters, digits and underscores, can't start with
+// underscore or digit and can't be longer than 64 characters. Keys of fields
+// which are set by JournalOutput itself are prefixed by FIELD_, so they don't
+// duplicate MESSAGE, PRIORITY and other fields of the entry.
+func journalFieldKey(key string) string {
+	key = strings.Map(func(symbol rune) rune {
+		switch {
+		case symbol >= 'a' && symbol <= 'z':
+			return symbol - 'a' + 'A'
+		case symbol >= 'A' && symbol <= 'Z', symbol >= '0' && symbol <= '9':
+			return symbol
+		}
+
+		return '_'
+	}, key)
+
+	key = strings.TrimLeft(key, "_0123456789")
+
+	switch {
+	case key == "MESSAGE", key == "PRIORITY", key == "SYSLOG_IDENTIFIER",
+		strings.HasPrefix(key, "CODE_"):
+		key = "FIELD_" + key
+	}
+
+	if len(key) > journalMaxFieldKeyLength {
+		key = key[:journalMaxFieldKeyLength]
+	}
+
+	return key
+}
+
+func formatJournalValue(value interface{}) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+
+	return formatFieldValue(value)
+}
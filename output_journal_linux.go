@@ -0,0 +1,99 @@
+package lorg
+
+import (
+	"errors"
+	"net"
+	"os"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	memfdCloexec      = 0x1
+	memfdAllowSealing = 0x2
+	fcntlAddSeals     = 1033
+	memfdSeals        = 0x1 | 0x2 | 0x4 | 0x8
+)
+
+// memfdCreateSyscalls are numbers of memfd_create syscall, syscall package
+// doesn't define it for all architectures.
+var memfdCreateSyscalls = map[string]uintptr{
+	"386":     356,
+	"amd64":   319,
+	"arm":     385,
+	"arm64":   279,
+	"loong64": 279,
+	"ppc64":   360,
+	"ppc64le": 360,
+	"riscv64": 279,
+	"s390x":   350,
+}
+
+// sendJournalFile passes given entry to journald as a sealed memfd or as an
+// unlinked temporary file in /dev/shm if memfd can't be created.
+func sendJournalFile(
+	conn *net.UnixConn, address *net.UnixAddr, entry []byte,
+) error {
+	file, err := createJournalFile()
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	_, err = file.Write(entry)
+	if err != nil {
+		return err
+	}
+
+	// sealing is required for memfd and not supported for temporary files
+	_, _, _ = syscall.Syscall(
+		syscall.SYS_FCNTL, file.Fd(), fcntlAddSeals, memfdSeals,
+	)
+
+	_, _, err = conn.WriteMsgUnix(
+		nil, syscall.UnixRights(int(file.Fd())), address,
+	)
+
+	return err
+}
+
+func createJournalFile() (*os.File, error) {
+	number, ok := memfdCreateSyscalls[runtime.GOARCH]
+	if ok {
+		name, err := syscall.BytePtrFromString("journal-entry")
+		if err != nil {
+			return nil, err
+		}
+
+		fd, _, errno := syscall.Syscall(
+			number,
+			uintptr(unsafe.Pointer(name)),
+			memfdCloexec|memfdAllowSealing,
+			0,
+		)
+		if errno == 0 {
+			return os.NewFile(fd, "journal-entry"), nil
+		}
+	}
+
+	file, err := os.CreateTemp("/dev/shm", "journal-entry-*")
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.Remove(file.Name())
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// isJournalEntryTooLarge reports whether entry can not be sent in datagram
+// because of it's size, so it should be sent using file.
+func isJournalEntryTooLarge(err error) bool {
+	return errors.Is(err, syscall.EMSGSIZE) || errors.Is(err, syscall.ENOBUFS)
+}
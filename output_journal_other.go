@@ -0,0 +1,22 @@
+//go:build !linux
+
+package lorg
+
+import (
+	"errors"
+	"net"
+)
+
+// sendJournalFile is not supported outside of Linux, because journald is
+// available only on Linux.
+func sendJournalFile(
+	conn *net.UnixConn, address *net.UnixAddr, entry []byte,
+) error {
+	return errors.New("journal entry is too large")
+}
+
+// isJournalEntryTooLarge always returns false outside of Linux, because entry
+// can not be sent using file anyway.
+func isJournalEntryTooLarge(err error) bool {
+	return false
+}
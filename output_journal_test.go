@@ -0,0 +1,155 @@
+//go:build linux
+
+package lorg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJournalOutput_WriteRecord_SendsFields(t *testing.T) {
+	test := assert.New(t)
+
+	server, path := listenJournal(t)
+	defer server.Close()
+
+	journal, err := NewJournalOutput(path)
+	test.NoError(err)
+	defer journal.Close()
+
+	log := NewLog()
+	log.SetOutput(journal)
+	log.SetFormat(NewFormat(`%s`))
+	log.SetPrefix("app")
+
+	_, file, line, _ := runtime.Caller(0)
+	log.With("user", "alice", "_trusted", 1).Warningw(
+		"multi\nline", "request-id", 12,
+		"priority", "high", "message", "m", "code_line", 7,
+		strings.Repeat("k", 70), "long",
+	)
+
+	packet := make([]byte, 4096)
+	size, err := server.Read(packet)
+	test.NoError(err)
+
+	test.Equal(
+		map[string]string{
+			"MESSAGE":           "multi\nline",
+			"PRIORITY":          "4",
+			"SYSLOG_IDENTIFIER": "app",
+			"CODE_FILE":         file,
+			"CODE_LINE":         strconv.Itoa(line + 1),
+			"CODE_FUNC": "github.com/kovetskiy/lorg." +
+				"TestJournalOutput_WriteRecord_SendsFields",
+			"USER":                  "alice",
+			"TRUSTED":               "1",
+			"REQUEST_ID":            "12",
+			"FIELD_PRIORITY":        "high",
+			"FIELD_MESSAGE":         "m",
+			"FIELD_CODE_LINE":       "7",
+			strings.Repeat("K", 64): "long",
+		},
+		parseJournalEntry(t, packet[:size]),
+	)
+}
+
+func TestJournalOutput_WriteRecord_PassesLargeEntriesAsFile(t *testing.T) {
+	test := assert.New(t)
+
+	server, path := listenJournal(t)
+	defer server.Close()
+
+	journal, err := NewJournalOutput(path)
+	test.NoError(err)
+	defer journal.Close()
+
+	journal.SetIdentifier("service")
+
+	message := strings.Repeat("x", 4*1024*1024)
+
+	_, err = journal.WriteWithLevel([]byte(message+"\n"), LevelError)
+	test.NoError(err)
+
+	oob := make([]byte, syscall.CmsgSpace(4))
+	_, oobSize, _, _, err := server.ReadMsgUnix(nil, oob)
+	test.NoError(err)
+
+	messages, err := syscall.ParseSocketControlMessage(oob[:oobSize])
+	test.NoError(err)
+	test.Len(messages, 1)
+
+	fds, err := syscall.ParseUnixRights(&messages[0])
+	test.NoError(err)
+	test.Len(fds, 1)
+
+	file := os.NewFile(uintptr(fds[0]), "entry")
+	defer file.Close()
+
+	_, err = file.Seek(0, io.SeekStart)
+	test.NoError(err)
+
+	data, err := io.ReadAll(file)
+	test.NoError(err)
+
+	test.Equal(
+		map[string]string{
+			"MESSAGE":           message,
+			"PRIORITY":          "3",
+			"SYSLOG_IDENTIFIER": "service",
+		},
+		parseJournalEntry(t, data),
+	)
+}
+
+func listenJournal(t *testing.T) (*net.UnixConn, string) {
+	path := filepath.Join(t.TempDir(), "socket")
+
+	server, err := net.ListenUnixgram(
+		"unixgram", &net.UnixAddr{Name: path, Net: "unixgram"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return server, path
+}
+
+func parseJournalEntry(t *testing.T, data []byte) map[string]string {
+	fields := map[string]string{}
+
+	for len(data) > 0 {
+		end := bytes.IndexAny(data, "=\n")
+		if end < 0 {
+			t.Fatalf("invalid entry: %q", data)
+		}
+
+		key := string(data[:end])
+
+		if data[end] == '=' {
+			data = data[end+1:]
+			newline := bytes.IndexByte(data, '\n')
+			fields[key] = string(data[:newline])
+			data = data[newline+1:]
+			continue
+		}
+
+		data = data[end+1:]
+		size := binary.LittleEndian.Uint64(data[:8])
+		fields[key] = string(data[8 : 8+size])
+		data = data[8+size+1:]
+	}
+
+	return fields
+}
@@ -0,0 +1,401 @@
+package lorg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SyslogFacility describes syslog facility of records.
+type SyslogFacility int
+
+// Syslog facilities according to RFC 5424.
+const (
+	SyslogKern SyslogFacility = iota
+	SyslogUser
+	SyslogMail
+	SyslogDaemon
+	SyslogAuth
+	SyslogSyslog
+	SyslogLPR
+	SyslogNews
+	SyslogUUCP
+	SyslogCron
+	SyslogAuthPriv
+	SyslogFTP
+	_
+	_
+	_
+	_
+	SyslogLocal0
+	SyslogLocal1
+	SyslogLocal2
+	SyslogLocal3
+	SyslogLocal4
+	SyslogLocal5
+	SyslogLocal6
+	SyslogLocal7
+)
+
+// SyslogProtocol describes format of syslog messages.
+type SyslogProtocol int
+
+const (
+	// SyslogRFC3164 is the BSD syslog format:
+	// <PRI>Jan  2 09:21:44 hostname app[pid]: message
+	SyslogRFC3164 SyslogProtocol = iota
+
+	// SyslogRFC5424 is the IETF syslog format:
+	// <PRI>1 2016-01-02T09:21:44.000000+03:00 hostname app pid - - message
+	SyslogRFC5424
+)
+
+const (
+	// syslogDialTimeout limits time of connecting to syslog server, because
+	// connecting blocks writing records.
+	syslogDialTimeout = 5 * time.Second
+
+	// syslogMinReconnectBackoff and syslogMaxReconnectBackoff are default
+	// reconnect backoff periods, see SyslogOutput.SetReconnectBackoff.
+	syslogMinReconnectBackoff = time.Second
+	syslogMaxReconnectBackoff = time.Minute
+)
+
+// ErrSyslogDisconnected is returned by SyslogOutput if a record has not been
+// sent because reconnecting to syslog server is postponed after failure, see
+// SyslogOutput.SetReconnectBackoff.
+var ErrSyslogDisconnected = errors.New("syslog server is disconnected")
+
+var (
+	// syslogLocalAddresses are unix sockets which are tried by
+	// NewSyslogOutput if network and address are not specified.
+	syslogLocalAddresses = []string{
+		"/dev/log", "/var/run/syslog", "/var/run/log",
+	}
+)
+
+// ensure that SyslogOutput implements SmartOutput interface.
+var _ SmartOutput = (*SyslogOutput)(nil)
+
+// SyslogOutput is the SmartOutput which sends log records to syslog server,
+// logging levels are mapped to syslog severities, so LevelFatal is sent as
+// critical and LevelTrace is sent as debug.
+//
+// Records are sent as they are formatted by Log format, so format without
+// time and level placeholders should be used: `${prefix}%s${fields}`.
+//
+// SyslogOutput reconnects to the server if write fails, records are dropped
+// with ErrSyslogDisconnected until reconnect backoff period passes if
+// reconnecting fails.
+//
+// Do not instantiate SyslogOutput instance without using NewSyslogOutput.
+type SyslogOutput struct {
+	network  string
+	address  string
+	conn     net.Conn
+	local    bool
+	facility SyslogFacility
+	protocol SyslogProtocol
+	appName  string
+	hostname string
+	pid      int
+	mutex    *sync.Mutex
+
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	backoff    time.Duration
+	retryAt    time.Time
+}
+
+// NewSyslogOutput connects to syslog server using given network ("udp",
+// "tcp", "unixgram" or "unix") and address. If network and address are
+// empty, NewSyslogOutput connects to the local syslog daemon using /dev/log
+// or similar unix socket.
+//
+// Default facility is SyslogUser, default protocol is SyslogRFC3164, default
+// app name is the base name of the executable.
+func NewSyslogOutput(network, address string) (*SyslogOutput, error) {
+	hostname, _ := os.Hostname()
+
+	syslog := &SyslogOutput{
+		network:  network,
+		address:  address,
+		local:    network == "" && address == "",
+		facility: SyslogUser,
+		protocol: SyslogRFC3164,
+		appName:  filepath.Base(os.Args[0]),
+		hostname: hostname,
+		pid:      os.Getpid(),
+		mutex:    &sync.Mutex{},
+
+		minBackoff: syslogMinReconnectBackoff,
+		maxBackoff: syslogMaxReconnectBackoff,
+	}
+
+	err := syslog.connect()
+	if err != nil {
+		return nil, err
+	}
+
+	return syslog, nil
+}
+
+// SetFacility sets facility of sent records.
+func (syslog *SyslogOutput) SetFacility(
+	facility SyslogFacility,
+) *SyslogOutput {
+	syslog.mutex.Lock()
+	syslog.facility = facility
+	syslog.mutex.Unlock()
+
+	return syslog
+}
+
+// SetProtocol sets format of sent messages, SyslogRFC3164 or SyslogRFC5424.
+func (syslog *SyslogOutput) SetProtocol(
+	protocol SyslogProtocol,
+) *SyslogOutput {
+	syslog.mutex.Lock()
+	syslog.protocol = protocol
+	syslog.mutex.Unlock()
+
+	return syslog
+}
+
+// SetAppName sets application name (tag) of sent records.
+func (syslog *SyslogOutput) SetAppName(name string) *SyslogOutput {
+	syslog.mutex.Lock()
+	syslog.appName = name
+	syslog.mutex.Unlock()
+
+	return syslog
+}
+
+// SetHostname sets hostname of sent records.
+func (syslog *SyslogOutput) SetHostname(hostname string) *SyslogOutput {
+	syslog.mutex.Lock()
+	syslog.hostname = hostname
+	syslog.mutex.Unlock()
+
+	return syslog
+}
+
+// SetReconnectBackoff sets period which should pass after failed reconnect
+// before the next reconnect, the period starts from minBackoff and doubles
+// after every failed reconnect up to maxBackoff. Default backoff starts from
+// one second up to one minute.
+func (syslog *SyslogOutput) SetReconnectBackoff(
+	minBackoff, maxBackoff time.Duration,
+) *SyslogOutput {
+	syslog.mutex.Lock()
+	syslog.minBackoff = minBackoff
+	syslog.maxBackoff = maxBackoff
+	syslog.mutex.Unlock()
+
+	return syslog
+}
+
+// Write sends given data with LevelInfo level.
+func (syslog *SyslogOutput) Write(data []byte) (int, error) {
+	return syslog.WriteWithLevel(data, LevelInfo)
+}
+
+// WriteWithLevel sends given data with severity which corresponds to given
+// level, trailing newline is removed from data.
+func (syslog *SyslogOutput) WriteWithLevel(
+	data []byte, level Level,
+) (int, error) {
+	syslog.mutex.Lock()
+	defer syslog.mutex.Unlock()
+
+	message := syslog.frame(
+		bytes.TrimRight(data, "\n"), level, time.Now(),
+	)
+
+	var err error
+	if syslog.conn != nil {
+		_, err = syslog.conn.Write(message)
+		if err == nil {
+			return len(data), nil
+		}
+
+		syslog.conn.Close()
+		syslog.conn = nil
+	}
+
+	connectErr := syslog.reconnect()
+	if connectErr != nil {
+		return 0, errors.Join(err, connectErr)
+	}
+
+	_, err = syslog.conn.Write(message)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(data), nil
+}
+
+// Close closes connection to syslog server.
+func (syslog *SyslogOutput) Close() error {
+	syslog.mutex.Lock()
+	defer syslog.mutex.Unlock()
+
+	if syslog.conn == nil {
+		return nil
+	}
+
+	err := syslog.conn.Close()
+	syslog.conn = nil
+
+	return err
+}
+
+// reconnect connects to syslog server unless reconnecting is postponed by
+// backoff after previous failure.
+func (syslog *SyslogOutput) reconnect() error {
+	now := time.Now()
+	if now.Before(syslog.retryAt) {
+		return fmt.Errorf(
+			"%w, reconnecting in %s",
+			ErrSyslogDisconnected, syslog.retryAt.Sub(now),
+		)
+	}
+
+	err := syslog.connect()
+	if err != nil {
+		if syslog.backoff == 0 {
+			syslog.backoff = syslog.minBackoff
+		} else {
+			syslog.backoff *= 2
+		}
+
+		if syslog.backoff > syslog.maxBackoff {
+			syslog.backoff = syslog.maxBackoff
+		}
+
+		syslog.retryAt = now.Add(syslog.backoff)
+
+		return err
+	}
+
+	syslog.backoff = 0
+	syslog.retryAt = time.Time{}
+
+	return nil
+}
+
+func (syslog *SyslogOutput) connect() error {
+	if !syslog.local {
+		conn, err := net.DialTimeout(
+			syslog.network, syslog.address, syslogDialTimeout,
+		)
+		if err != nil {
+			return err
+		}
+
+		syslog.conn = conn
+
+		return nil
+	}
+
+	for _, network := range []string{"unixgram", "unix"} {
+		for _, address := range syslogLocalAddresses {
+			conn, err := net.DialTimeout(network, address, syslogDialTimeout)
+			if err == nil {
+				syslog.network = network
+				syslog.address = address
+				syslog.conn = conn
+
+				return nil
+			}
+		}
+	}
+
+	return errors.New("can't connect to local syslog server")
+}
+
+func (syslog *SyslogOutput) frame(
+	data []byte, level Level, now time.Time,
+) []byte {
+	priority := int(syslog.facility)*8 + syslogSeverity(level)
+
+	buffer := &bytes.Buffer{}
+
+	buffer.WriteString("<" + strconv.Itoa(priority) + ">")
+
+	switch syslog.protocol {
+	case SyslogRFC5424:
+		buffer.WriteString("1 ")
+		buffer.WriteString(now.Format("2006-01-02T15:04:05.000000Z07:00"))
+		buffer.WriteString(" " + syslogHeaderValue(syslog.hostname, 255))
+		buffer.WriteString(" " + syslogHeaderValue(syslog.appName, 48))
+		buffer.WriteString(" " + strconv.Itoa(syslog.pid) + " - - ")
+
+	default:
+		buffer.WriteString(now.Format(time.Stamp) + " ")
+		// local daemons add hostname by themselves
+		if !syslog.local {
+			buffer.WriteString(syslogHeaderValue(syslog.hostname, 255) + " ")
+		}
+
+		buffer.WriteString(
+			syslog.appName + "[" + strconv.Itoa(syslog.pid) + "]: ",
+		)
+	}
+
+	buffer.Write(data)
+
+	switch syslog.network {
+	case "tcp", "tcp4", "tcp6", "unix":
+		if syslog.protocol == SyslogRFC5424 {
+			// octet counting framing, RFC 6587
+			return append(
+				[]byte(strconv.Itoa(buffer.Len())+" "), buffer.Bytes()...,
+			)
+		}
+
+		buffer.WriteByte('\n')
+	}
+
+	return buffer.Bytes()
+}
+
+func syslogSeverity(level Level) int {
+	switch level {
+	case LevelFatal:
+		return 2
+	case LevelError:
+		return 3
+	case LevelWarning:
+		return 4
+	case LevelInfo:
+		return 6
+	}
+
+	return 7
+}
+
+// syslogHeaderValue returns given value without spaces and not longer than
+// given length or "-" if value is empty, as RFC 5424 requires.
+func syslogHeaderValue(value string, length int) string {
+	if value == "" {
+		return "-"
+	}
+
+	value = fmt.Sprintf("%.*s", length, value)
+
+	return string(bytes.Map(func(symbol rune) rune {
+		if symbol <= ' ' || symbol > '~' {
+			return '_'
+		}
+
+		return symbol
+	}, []byte(value)))
+}
@@ -0,0 +1,190 @@
+package lorg
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyslogOutput_WriteWithLevel_SendsRFC3164ToLocalSocket(
+	t *testing.T,
+) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix datagram sockets are not supported")
+	}
+
+	test := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "log")
+
+	server, err := net.ListenUnixgram(
+		"unixgram", &net.UnixAddr{Name: path, Net: "unixgram"},
+	)
+	test.NoError(err)
+	defer server.Close()
+
+	defer func(addresses []string) {
+		syslogLocalAddresses = addresses
+	}(syslogLocalAddresses)
+
+	syslogLocalAddresses = []string{path}
+
+	syslog, err := NewSyslogOutput("", "")
+	test.NoError(err)
+	defer syslog.Close()
+
+	syslog.SetFacility(SyslogLocal0).SetAppName("app")
+
+	log := NewLog()
+	log.SetOutput(syslog)
+	log.SetFormat(NewFormat(`${prefix}%s`))
+
+	log.Warning("disk is full")
+
+	packet := make([]byte, 1024)
+	size, err := server.Read(packet)
+	test.NoError(err)
+
+	test.Regexp(
+		`^<132>\w{3} [ \d]\d \d\d:\d\d:\d\d app\[`+
+			strconv.Itoa(os.Getpid())+`\]: disk is full$`,
+		string(packet[:size]),
+	)
+}
+
+func TestSyslogOutput_WriteWithLevel_SendsRFC5424OverTCPAndReconnects(
+	t *testing.T,
+) {
+	test := assert.New(t)
+
+	server, err := net.Listen("tcp", "127.0.0.1:0")
+	test.NoError(err)
+	defer server.Close()
+
+	syslog, err := NewSyslogOutput("tcp", server.Addr().String())
+	test.NoError(err)
+	defer syslog.Close()
+
+	syslog.SetProtocol(SyslogRFC5424).
+		SetFacility(SyslogDaemon).
+		SetAppName("app").
+		SetHostname("host")
+
+	conn, err := server.Accept()
+	test.NoError(err)
+
+	_, err = syslog.WriteWithLevel([]byte("first\n"), LevelError)
+	test.NoError(err)
+
+	reader := bufio.NewReader(conn)
+
+	frame := readOctetCountedFrame(t, reader)
+	test.Regexp(
+		`^<27>1 \S+ host app `+strconv.Itoa(os.Getpid())+` - - first$`,
+		frame,
+	)
+
+	// server drops connection, so syslog should reconnect
+	conn.Close()
+
+	// first writes after closing connection can succeed, because kernel
+	// buffers data, so write until server accepts new connection.
+	stop := make(chan struct{})
+	defer close(stop)
+
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+
+			syslog.WriteWithLevel([]byte("second\n"), LevelTrace)
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	conn, err = server.Accept()
+	test.NoError(err)
+	defer conn.Close()
+
+	frame = readOctetCountedFrame(t, bufio.NewReader(conn))
+	test.Regexp(`^<31>1 \S+ host app \d+ - - second$`, frame)
+}
+
+func readOctetCountedFrame(t *testing.T, reader *bufio.Reader) string {
+	length, err := reader.ReadString(' ')
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	size, err := strconv.Atoi(length[:len(length)-1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frame := make([]byte, size)
+	_, err = io.ReadFull(reader, frame)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(frame)
+}
+
+func TestSyslogOutput_WriteWithLevel_DropsRecordsUntilReconnectBackoff(
+	t *testing.T,
+) {
+	test := assert.New(t)
+
+	server, err := net.Listen("tcp", "127.0.0.1:0")
+	test.NoError(err)
+
+	syslog, err := NewSyslogOutput("tcp", server.Addr().String())
+	test.NoError(err)
+	defer syslog.Close()
+
+	syslog.SetReconnectBackoff(50*time.Millisecond, time.Second)
+
+	conn, err := server.Accept()
+	test.NoError(err)
+
+	conn.Close()
+	server.Close()
+
+	// first writes after closing connection can succeed, because kernel
+	// buffers data
+	for i := 0; i < 100; i++ {
+		_, err = syslog.WriteWithLevel([]byte("lost\n"), LevelInfo)
+		if err != nil {
+			break
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	test.Error(err)
+	test.NotErrorIs(err, ErrSyslogDisconnected)
+
+	_, err = syslog.WriteWithLevel([]byte("dropped\n"), LevelInfo)
+	test.ErrorIs(err, ErrSyslogDisconnected)
+
+	time.Sleep(60 * time.Millisecond)
+
+	_, err = syslog.WriteWithLevel([]byte("retried\n"), LevelInfo)
+	test.Error(err)
+	test.NotErrorIs(err, ErrSyslogDisconnected)
+
+	_, err = syslog.WriteWithLevel([]byte("dropped\n"), LevelInfo)
+	test.ErrorIs(err, ErrSyslogDisconnected)
+	test.Equal(100*time.Millisecond, syslog.backoff)
+}
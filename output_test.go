@@ -2,7 +2,9 @@ package lorg
 
 import (
 	"bytes"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -31,3 +33,192 @@ func TestOutput_SetLevelWriterCondition_SetsAdditionalWriter(t *testing.T) {
 	test.EqualValues(buffer1.String(), "WARNING 2\n")
 	test.EqualValues(buffer2.String(), "WARNING 2\n")
 }
+
+func TestOutput_Route_WritesMatchingRecordsToAdditionalWriters(t *testing.T) {
+	test := assert.New(t)
+
+	var stderr bytes.Buffer
+	var errors bytes.Buffer
+	var verbose bytes.Buffer
+	var db bytes.Buffer
+
+	logger := NewLog()
+	logger.SetOutput(
+		NewOutput(&stderr).
+			Route(MinLevel(LevelWarning), &errors).
+			Route(Between(LevelTrace, LevelDebug), &verbose).
+			Route(Any(PrefixIs("db"), HasField("query")), &db, &errors),
+	)
+	logger.SetFormat(NewFormat("${level} %s"))
+	logger.SetLevel(LevelTrace)
+
+	logger.Info("1")
+	logger.Error("2")
+	logger.Trace("3")
+	logger.NewChildWithPrefix("db").Warning("4")
+	logger.Debugw("5", "query", "select")
+
+	test.EqualValues(
+		"INFO 1\nERROR 2\nTRACE 3\nWARNING 4\nDEBUG 5\n", stderr.String(),
+	)
+	test.EqualValues("ERROR 2\nWARNING 4\nDEBUG 5\n", errors.String())
+	test.EqualValues("TRACE 3\nDEBUG 5\n", verbose.String())
+	test.EqualValues("WARNING 4\nDEBUG 5\n", db.String())
+}
+
+func TestOutput_RouteOnly_WritesMatchingRecordsOnlyToGivenWriters(
+	t *testing.T,
+) {
+	test := assert.New(t)
+
+	var stderr bytes.Buffer
+	var audit bytes.Buffer
+	var errors bytes.Buffer
+
+	logger := NewLog()
+	logger.SetOutput(
+		NewOutput(&stderr).
+			RouteOnly(FieldIs("audit", true), &audit).
+			Route(MinLevel(LevelError), &errors),
+	)
+	logger.SetFormat(NewFormat("${level} %s"))
+
+	logger.Errorw("1", "audit", true)
+	logger.Error("2")
+
+	test.EqualValues("ERROR 2\n", stderr.String())
+	test.EqualValues("ERROR 1\n", audit.String())
+	test.EqualValues("ERROR 2\n", errors.String())
+}
+
+type failingWriter struct {
+	calls int
+}
+
+func (writer *failingWriter) Write(data []byte) (int, error) {
+	writer.calls++
+	return 0, fmt.Errorf("failure #%d", writer.calls)
+}
+
+func TestOutput_WriteWithLevel_JoinsErrorsOfAllWriters(t *testing.T) {
+	test := assert.New(t)
+
+	var buffer bytes.Buffer
+
+	first := &failingWriter{}
+	second := &failingWriter{}
+
+	output := NewOutput(first).SetLevelWriterCondition(
+		LevelInfo, first, &buffer, second,
+	)
+
+	written, err := output.WriteWithLevel([]byte("1\n"), LevelInfo)
+	test.Equal(2, written)
+	test.EqualError(err, "failure #1\nfailure #1")
+	test.Equal("1\n", buffer.String())
+}
+
+func TestOutput_WriteWithLevel_UsesFallbackAndErrorHandler(t *testing.T) {
+	test := assert.New(t)
+
+	var fallback bytes.Buffer
+
+	primary := &failingWriter{}
+	broken := &failingWriter{}
+
+	var handled []error
+
+	output := NewOutput(primary).
+		SetFallbackWriter(broken, &fallback).
+		SetErrorHandler(func(err error) {
+			handled = append(handled, err)
+		})
+
+	written, err := output.WriteWithLevel([]byte("1\n"), LevelInfo)
+	test.NoError(err)
+	test.Equal(2, written)
+	test.Equal("1\n", fallback.String())
+	test.Len(handled, 1)
+	test.EqualError(handled[0], "failure #1\nfailure #1")
+}
+
+func TestOutput_WriteWithLevel_DisablesFailingWriterWithBackoff(
+	t *testing.T,
+) {
+	test := assert.New(t)
+
+	var fallback bytes.Buffer
+
+	now := time.Now()
+	primary := &failingWriter{}
+
+	output := NewOutput(primary).
+		SetFallbackWriter(&fallback).
+		SetRetryPolicy(2, time.Second, 3*time.Second)
+	output.now = func() time.Time {
+		return now
+	}
+
+	write := func() error {
+		_, err := output.WriteWithLevel([]byte("."), LevelInfo)
+		return err
+	}
+
+	test.EqualError(write(), "failure #1")
+	test.EqualError(
+		write(),
+		"writer *lorg.failingWriter is disabled for 1s: failure #2",
+	)
+
+	// writer is disabled, so it's not called
+	test.NoError(write())
+	test.Equal(2, primary.calls)
+
+	now = now.Add(time.Second)
+	test.EqualError(
+		write(),
+		"writer *lorg.failingWriter is disabled for 2s: failure #3",
+	)
+
+	now = now.Add(2 * time.Second)
+	test.EqualError(
+		write(),
+		"writer *lorg.failingWriter is disabled for 3s: failure #4",
+	)
+
+	test.Equal(".....", fallback.String())
+}
+
+func TestOutput_WriteWithLevel_ReportsRecordsOfDisabledWriters(
+	t *testing.T,
+) {
+	test := assert.New(t)
+
+	primary := &failingWriter{}
+
+	var handled []error
+
+	output := NewOutput(primary).SetErrorHandler(func(err error) {
+		handled = append(handled, err)
+	})
+
+	for i := 0; i < 10; i++ {
+		output.WriteWithLevel([]byte("."), LevelInfo)
+	}
+
+	// writers are not disabled by default
+	test.Equal(10, primary.calls)
+	test.Len(handled, 10)
+
+	handled = nil
+	output.SetRetryPolicy(1, time.Minute, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		output.WriteWithLevel([]byte("."), LevelInfo)
+	}
+
+	test.Equal(11, primary.calls)
+	test.Len(handled, 3)
+	test.ErrorIs(handled[1], ErrWriterDisabled)
+	test.ErrorIs(handled[2], ErrWriterDisabled)
+}
@@ -2,7 +2,9 @@ package lorg
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"runtime"
 	"strconv"
@@ -27,6 +29,20 @@ import (
 //     * ${level:a:b:c} - value will be "a:b:c"
 type Placeholder func(logLevel Level, value string) string
 
+// RecordPlaceholder is the same as Placeholder, but it receives the whole
+// log record, so it can use message, prefix, fields, time and caller of the
+// record which is captured once by Log, instead of guessing stack depth.
+//
+// Record placeholder is preferred if placeholder with the same name is set
+// too, so time, file and line of DefaultPlaceholders are rendered by
+// DefaultRecordPlaceholders. Setting placeholder with the same name as record
+// placeholder using SetPlaceholder or SetPlaceholders replaces record
+// placeholder, SetRecordPlaceholder replaces placeholder the same way.
+type RecordPlaceholder func(record *Record, value string) string
+
+// recordAppender appends result of record placeholder to given buffer.
+type recordAppender func(buffer []byte, record *Record, value string) []byte
+
 type cacheHash struct {
 	hash zhash.Hash
 	*sync.RWMutex
@@ -37,12 +53,53 @@ var (
 
 	// DefaultPlaceholders that will be used for new Log instances.
 	DefaultPlaceholders = map[string]Placeholder{
-		"level": PlaceholderLevel,
-		"line":  PlaceholderLine,
-		"file":  PlaceholderFile,
-		"time":  PlaceholderTime,
+		"level":      PlaceholderLevel,
+		"line":       PlaceholderLine,
+		"file":       PlaceholderFile,
+		"time":       PlaceholderTime,
+		"color":      PlaceholderColor,
+		"reset":      PlaceholderReset,
+		"colorlevel": PlaceholderColorLevel,
+		"goroutine":  PlaceholderGoroutine,
+		"pid":        PlaceholderPID,
+		"hostname":   PlaceholderHostname,
+	}
+
+	// DefaultRecordPlaceholders that will be used for new Log instances.
+	DefaultRecordPlaceholders = map[string]RecordPlaceholder{
+		"line":    RecordPlaceholderLine,
+		"file":    RecordPlaceholderFile,
+		"time":    RecordPlaceholderTime,
+		"func":    RecordPlaceholderFunc,
+		"package": RecordPlaceholderPackage,
+		"stack":   RecordPlaceholderStack,
+	}
+
+	// staticPlaceholders contains placeholders which results depend only on
+	// level and placeholder value, so Format renders them once for every
+	// level.
+	staticPlaceholders = map[uintptr]bool{
+		funcPointer(PlaceholderLevel):                         true,
+		funcPointer(PlaceholderColor):                         true,
+		funcPointer(PlaceholderReset):                         true,
+		funcPointer(PlaceholderColorLevel):                    true,
+		funcPointer(NewPlaceholderColor(DefaultPalette)):      true,
+		funcPointer(NewPlaceholderColorLevel(DefaultPalette)): true,
+		funcPointer(PlaceholderPID):                           true,
+		funcPointer(PlaceholderHostname):                      true,
+		funcPointer(placeholderEmpty):                         true,
 	}
 
+	// recordAppenders are used by Format instead of record placeholders for
+	// appending their results without allocations.
+	recordAppenders = map[uintptr]recordAppender{
+		funcPointer(RecordPlaceholderTime): appendRecordTime,
+		funcPointer(RecordPlaceholderLine): appendRecordLine,
+	}
+
+	hostname     string
+	hostnameOnce = &sync.Once{}
+
 	cache = &cacheHash{zhash.NewHash(), &sync.RWMutex{}}
 )
 
@@ -136,6 +193,10 @@ func PlaceholderLevel(logLevel Level, optional string) string {
 
 // PlaceholderLine returns a file line where has been called logging function.
 //
+// PlaceholderLine uses fixed stack depth, so it returns wrong line if logging
+// function is called through wrapper, RecordPlaceholderLine is used by
+// default instead.
+//
 // Using: ${line}
 func PlaceholderLine(logLevel Level, _ string) string {
 	_, _, line, ok := runtime.Caller(placeholderCallStackLevel)
@@ -153,17 +214,17 @@ func PlaceholderLine(logLevel Level, _ string) string {
 //                     Using: ${file:short} or just ${file}
 //    * "long":    a final file name will be retuned as is.
 //                     Using: ${file:long}
+//
+// PlaceholderFile uses fixed stack depth, so it returns wrong file if logging
+// function is called through wrapper, RecordPlaceholderFile is used by
+// default instead.
 func PlaceholderFile(logLevel Level, mode string) string {
 	_, file, _, ok := runtime.Caller(placeholderCallStackLevel)
-	if !ok || file == "" {
+	if !ok {
 		return "??"
 	}
 
-	if mode == "long" {
-		return file
-	}
-
-	return filepath.Base(file)
+	return formatFile(file, mode)
 }
 
 // PlaceholderTime returns current time formatted with specified time
@@ -188,6 +249,185 @@ func PlaceholderTime(logLevel Level, layout string) string {
 	return time.Now().Format(layout)
 }
 
+// RecordPlaceholderLine returns a file line where has been called logging
+// function.
+//
+// Using: ${line}
+func RecordPlaceholderLine(record *Record, _ string) string {
+	if record.Line == 0 {
+		return "??"
+	}
+
+	return strconv.Itoa(record.Line)
+}
+
+func appendRecordLine(buffer []byte, record *Record, _ string) []byte {
+	if record.Line == 0 {
+		return append(buffer, "??"...)
+	}
+
+	return strconv.AppendInt(buffer, int64(record.Line), 10)
+}
+
+// RecordPlaceholderFile returns a file name where has been called logging
+// function, it works in the same modes as PlaceholderFile.
+//
+// Using: ${file}
+//
+//	${file:long}
+func RecordPlaceholderFile(record *Record, mode string) string {
+	return formatFile(record.File, mode)
+}
+
+// RecordPlaceholderTime returns time of the record formatted in the same
+// manner as PlaceholderTime.
+//
+// Using: ${time}
+//
+//	${time:timestamp}
+//	${time:15:04:05}
+func RecordPlaceholderTime(record *Record, layout string) string {
+	recordTime := record.Time
+	if recordTime.IsZero() {
+		recordTime = time.Now()
+	}
+
+	if layout == "timestamp" {
+		return strconv.FormatInt(recordTime.Unix(), 10)
+	}
+
+	if layout == "" {
+		layout = PlaceholderTimeDefaultLayout
+	}
+
+	return recordTime.Format(layout)
+}
+
+func appendRecordTime(buffer []byte, record *Record, layout string) []byte {
+	recordTime := record.Time
+	if recordTime.IsZero() {
+		recordTime = time.Now()
+	}
+
+	if layout == "timestamp" {
+		return strconv.AppendInt(buffer, recordTime.Unix(), 10)
+	}
+
+	if layout == "" {
+		layout = PlaceholderTimeDefaultLayout
+	}
+
+	return recordTime.AppendFormat(buffer, layout)
+}
+
+// RecordPlaceholderFunc returns name of the function where has been called
+// logging function. RecordPlaceholderFunc can work in two modes:
+//   - "short": default behaviour, function name without package path will be
+//     returned: (*Server).handle.
+//     Using: ${func:short} or just ${func}
+//   - "long": fully qualified function name will be returned:
+//     github.com/user/app/server.(*Server).handle.
+//     Using: ${func:long}
+func RecordPlaceholderFunc(record *Record, mode string) string {
+	if record.Function == "" {
+		return "??"
+	}
+
+	if mode == "long" {
+		return record.Function
+	}
+
+	_, function := splitFunction(record.Function)
+
+	return function
+}
+
+// RecordPlaceholderPackage returns package of the function where has been
+// called logging function. RecordPlaceholderPackage can work in two modes:
+//   - "short": default behaviour, package name will be returned: server.
+//     Using: ${package:short} or just ${package}
+//   - "long": package import path will be returned: github.com/user/app/server.
+//     Using: ${package:long}
+func RecordPlaceholderPackage(record *Record, mode string) string {
+	if record.Function == "" {
+		return "??"
+	}
+
+	pkg, _ := splitFunction(record.Function)
+	if mode == "long" {
+		return pkg
+	}
+
+	return pkg[strings.LastIndex(pkg, "/")+1:]
+}
+
+// PlaceholderGoroutine returns identifier of the goroutine which called
+// logging function.
+//
+// Using: ${goroutine}
+func PlaceholderGoroutine(_ Level, _ string) string {
+	var buffer [64]byte
+
+	// stack starts with "goroutine 1 [running]:"
+	stack := string(buffer[:runtime.Stack(buffer[:], false)])
+	stack = strings.TrimPrefix(stack, "goroutine ")
+
+	if index := strings.IndexByte(stack, ' '); index > 0 {
+		return stack[:index]
+	}
+
+	return "??"
+}
+
+// PlaceholderPID returns identifier of the process.
+//
+// Using: ${pid}
+func PlaceholderPID(_ Level, _ string) string {
+	return strconv.Itoa(os.Getpid())
+}
+
+// PlaceholderHostname returns host name reported by the kernel, host name is
+// determined once.
+//
+// Using: ${hostname}
+func PlaceholderHostname(_ Level, _ string) string {
+	hostnameOnce.Do(func() {
+		var err error
+		hostname, err = os.Hostname()
+		if err != nil {
+			hostname = "??"
+		}
+	})
+
+	return hostname
+}
+
+// splitFunction splits fully qualified function name to package import path
+// and function name: github.com/user/app/server.(*Server).handle is split to
+// github.com/user/app/server and (*Server).handle.
+func splitFunction(name string) (string, string) {
+	slash := strings.LastIndex(name, "/") + 1
+
+	dot := strings.IndexByte(name[slash:], '.')
+	if dot < 0 {
+		return "", name
+	}
+
+	return name[:slash+dot], name[slash+dot+1:]
+}
+
+func formatFile(file string, mode string) string {
+	if file == "" {
+		return "??"
+	}
+
+	if mode == "long" {
+		return file
+	}
+
+	return filepath.Base(file)
+}
+
 func isTrueString(str string) bool {
 	return str == "true" || str == "yes" || str == "1"
 }
@@ -240,3 +480,9 @@ func (cache *cacheHash) set(value interface{}, path ...string) {
 	cache.hash.Set(value, path...)
 	cache.Unlock()
 }
+
+// funcPointer returns code pointer of given function, so functions can be
+// compared.
+func funcPointer(function interface{}) uintptr {
+	return reflect.ValueOf(function).Pointer()
+}
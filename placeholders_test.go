@@ -3,6 +3,7 @@ package lorg
 import (
 	"bytes"
 	"fmt"
+	"os"
 	"path/filepath"
 	"runtime"
 	"strconv"
@@ -207,3 +208,93 @@ func callLoggerWithFormat(
 
 	return strings.TrimRight(string(buffer.Bytes()), "\n")
 }
+
+func TestRecordPlaceholders_ReturnCallerAndTimeOfRecord(t *testing.T) {
+	test := assert.New(t)
+
+	buffer := bytes.NewBuffer(nil)
+
+	log := NewLog()
+	log.SetOutput(buffer)
+	log.SetFormat(NewFormat(`${file} ${file:long} ${line} ${time:2006}`))
+
+	_, file, line, _ := runtime.Caller(0)
+	log.Info("")
+
+	test.Equal(
+		fmt.Sprintf(
+			"%s %s %d %s\n",
+			filepath.Base(file), file, line+1, time.Now().Format("2006"),
+		),
+		buffer.String(),
+	)
+
+	for _, name := range []string{"file", "line", "time"} {
+		test.Contains(DefaultPlaceholders, name)
+		test.Contains(DefaultRecordPlaceholders, name)
+	}
+
+	record := &Record{Time: time.Unix(1500000000, 0)}
+
+	test.Equal("??", RecordPlaceholderFile(record, ""))
+	test.Equal("??", RecordPlaceholderLine(record, ""))
+	test.Equal("1500000000", RecordPlaceholderTime(record, "timestamp"))
+}
+
+func TestRecordPlaceholders_ReturnFunctionAndPackageOfCaller(t *testing.T) {
+	test := assert.New(t)
+
+	buffer := bytes.NewBuffer(nil)
+
+	log := NewLog()
+	log.SetOutput(buffer)
+	log.SetFormat(NewFormat(
+		`${func} ${func:long} ${package} ${package:long} %s`,
+	))
+
+	log.Info("direct")
+	func() {
+		log.Info("closure")
+	}()
+
+	test.Equal(
+		"TestRecordPlaceholders_ReturnFunctionAndPackageOfCaller "+
+			"github.com/kovetskiy/lorg."+
+			"TestRecordPlaceholders_ReturnFunctionAndPackageOfCaller "+
+			"lorg github.com/kovetskiy/lorg direct\n"+
+			"TestRecordPlaceholders_ReturnFunctionAndPackageOfCaller.func1 "+
+			"github.com/kovetskiy/lorg."+
+			"TestRecordPlaceholders_ReturnFunctionAndPackageOfCaller.func1 "+
+			"lorg github.com/kovetskiy/lorg closure\n",
+		buffer.String(),
+	)
+
+	record := &Record{Function: "main.(*server).handle"}
+
+	test.Equal("(*server).handle", RecordPlaceholderFunc(record, ""))
+	test.Equal("main", RecordPlaceholderPackage(record, ""))
+	test.Equal("??", RecordPlaceholderFunc(&Record{}, ""))
+}
+
+func TestPlaceholders_ReturnProcessInformation(t *testing.T) {
+	test := assert.New(t)
+
+	hostname, err := os.Hostname()
+	test.NoError(err)
+
+	test.Equal(strconv.Itoa(os.Getpid()), PlaceholderPID(LevelInfo, ""))
+	test.Equal(hostname, PlaceholderHostname(LevelInfo, ""))
+
+	goroutines := make(chan string, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			goroutines <- PlaceholderGoroutine(LevelInfo, "")
+		}()
+	}
+
+	first, second := <-goroutines, <-goroutines
+
+	test.Regexp(`^\d+$`, first)
+	test.Regexp(`^\d+$`, second)
+	test.NotEqual(first, second)
+}
@@ -0,0 +1,46 @@
+package lorg
+
+import (
+	"context"
+	"runtime"
+	"time"
+)
+
+// Record is a single log record which is passed to RecordFormatter
+// implementations.
+type Record struct {
+	// Level is the logging level of the record.
+	Level Level
+
+	// Time is the time when the record has been created.
+	Time time.Time
+
+	// Prefix is the prefix of the logger which created the record.
+	Prefix string
+
+	// Message is the text of the record formatted in the manner of fmt.Print
+	// or fmt.Printf depending on the called logging function.
+	Message string
+
+	// Fields is the structured fields of the logger merged with fields passed
+	// to the logging function.
+	Fields Fields
+
+	// PC, File, Line and Function describe the place where logging function
+	// has been called, File and Function are empty and Line is zero if caller
+	// is unknown. Function is the fully qualified function name:
+	// github.com/kovetskiy/lorg.(*Log).Info.
+	PC       uintptr
+	File     string
+	Line     int
+	Function string
+
+	// Stack is the stack of the caller or of the error passed to logging
+	// function, it's captured only for levels specified by
+	// Log.SetStackTraceLevel.
+	Stack []runtime.Frame
+
+	// Context is the context passed to InfoContext-like logging functions or
+	// to SlogHandler, it's nil for other logging functions.
+	Context context.Context
+}
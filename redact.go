@@ -0,0 +1,291 @@
+package lorg
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// RedactedReplacement is the text which is used by Redactor instead of
+// sensitive data if replacement is not specified using SetReplacement.
+const RedactedReplacement = "[REDACTED]"
+
+var (
+	// RedactCreditCards matches numbers of Visa, MasterCard, American
+	// Express and Discover cards with optional spaces or dashes between
+	// digits.
+	RedactCreditCards = regexp.MustCompile(
+		`\b(?:4\d{3}|5[1-5]\d{2}|2[2-7]\d{2}|6(?:011|5\d{2})|3[47]\d{2})` +
+			`(?:[ -]?\d){9,15}\b`,
+	)
+
+	// RedactBearerTokens matches tokens of Authorization header values,
+	// only the token is redacted: "Bearer [REDACTED]".
+	RedactBearerTokens = regexp.MustCompile(
+		`(?i)\b(?:bearer|basic|token)\s+([a-z0-9\-._~+/]+=*)`,
+	)
+
+	// RedactEmails matches email addresses.
+	RedactEmails = regexp.MustCompile(
+		`[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`,
+	)
+
+	// DefaultRedactPatterns contains all built-in patterns.
+	DefaultRedactPatterns = []*regexp.Regexp{
+		RedactCreditCards, RedactBearerTokens, RedactEmails,
+	}
+
+	// DefaultRedactFields contains names of fields which usually contain
+	// sensitive data.
+	DefaultRedactFields = []string{
+		"password", "passwd", "secret", "token", "access_token",
+		"refresh_token", "api_key", "apikey", "authorization", "cookie",
+	}
+)
+
+// Redactable is the interface which can be implemented by types containing
+// sensitive data, Log uses value returned by Redacted instead of the value
+// itself if the value is passed to logging functions as an argument or as a
+// field:
+//
+//	func (request *Request) Redacted() interface{} {
+//		return request.Method + " " + request.Path
+//	}
+type Redactable interface {
+	Redacted() interface{}
+}
+
+// Redactor removes sensitive data from message, prefix and fields of records
+// before records are passed to hooks, formatter and output, records are
+// redacted again after hooks, so fields added by hooks are redacted too.
+// Text is redacted using patterns, values of fields are redacted if field
+// name is denied or formatted value matches patterns.
+//
+// Redactor is safe for concurrent use.
+//
+// Do not instantiate Redactor instance without using NewRedactor.
+type Redactor struct {
+	patterns    []*regexp.Regexp
+	fields      map[string]struct{}
+	replacement string
+	mutex       *sync.RWMutex
+}
+
+// NewRedactor creates Redactor without patterns and denied fields:
+//
+//	redactor := lorg.NewRedactor().
+//		AddPatterns(lorg.DefaultRedactPatterns...).
+//		AddFields(lorg.DefaultRedactFields...)
+func NewRedactor() *Redactor {
+	return &Redactor{
+		fields:      map[string]struct{}{},
+		replacement: RedactedReplacement,
+		mutex:       &sync.RWMutex{},
+	}
+}
+
+// AddPatterns adds patterns of sensitive data, whole match is replaced if
+// pattern has no groups, otherwise only text of groups is replaced.
+func (redactor *Redactor) AddPatterns(patterns ...*regexp.Regexp) *Redactor {
+	redactor.mutex.Lock()
+	redactor.patterns = append(redactor.patterns, patterns...)
+	redactor.mutex.Unlock()
+
+	return redactor
+}
+
+// AddFields adds names of fields which values should be always redacted,
+// names are case-insensitive.
+func (redactor *Redactor) AddFields(names ...string) *Redactor {
+	redactor.mutex.Lock()
+	for _, name := range names {
+		redactor.fields[strings.ToLower(name)] = struct{}{}
+	}
+	redactor.mutex.Unlock()
+
+	return redactor
+}
+
+// SetReplacement sets text which is used instead of sensitive data.
+func (redactor *Redactor) SetReplacement(replacement string) *Redactor {
+	redactor.mutex.Lock()
+	redactor.replacement = replacement
+	redactor.mutex.Unlock()
+
+	return redactor
+}
+
+// Redact returns given text with sensitive data replaced.
+func (redactor *Redactor) Redact(text string) string {
+	redactor.mutex.RLock()
+	defer redactor.mutex.RUnlock()
+
+	return redactor.redact(text)
+}
+
+// RedactFields returns given fields with sensitive values replaced, given
+// fields are not changed.
+func (redactor *Redactor) RedactFields(fields Fields) Fields {
+	redactor.mutex.RLock()
+	defer redactor.mutex.RUnlock()
+
+	var redacted Fields
+	for index, field := range fields {
+		value, ok := redactor.redactValue(field)
+		if !ok {
+			continue
+		}
+
+		if redacted == nil {
+			redacted = append(Fields(nil), fields...)
+		}
+
+		redacted[index].Value = value
+	}
+
+	if redacted == nil {
+		return fields
+	}
+
+	return redacted
+}
+
+// redactValue returns redacted value of given field and true if value
+// contains sensitive data, redactor mutex should be locked.
+func (redactor *Redactor) redactValue(field Field) (interface{}, bool) {
+	if _, ok := redactor.fields[strings.ToLower(field.Key)]; ok {
+		return redactor.replacement, true
+	}
+
+	if len(redactor.patterns) == 0 {
+		return nil, false
+	}
+
+	var text string
+	switch value := field.Value.(type) {
+	case string:
+		text = value
+	case nil, bool, int, int64, int32, uint, uint64, uint32, float64:
+		return nil, false
+	default:
+		text = fmt.Sprint(value)
+	}
+
+	redacted := redactor.redact(text)
+	if redacted == text {
+		return nil, false
+	}
+
+	return redacted, true
+}
+
+// redact replaces matches of patterns in given text, redactor mutex should
+// be locked.
+func (redactor *Redactor) redact(text string) string {
+	for _, pattern := range redactor.patterns {
+		matches := pattern.FindAllStringSubmatchIndex(text, -1)
+		if len(matches) == 0 {
+			continue
+		}
+
+		var builder strings.Builder
+
+		last := 0
+		for _, match := range matches {
+			// replace whole match or groups of the match
+			groups := [][]int{match[:2]}
+			if len(match) > 2 {
+				groups = nil
+				for index := 2; index < len(match); index += 2 {
+					if match[index] >= 0 && match[index] >= last {
+						groups = append(groups, match[index:index+2])
+					}
+				}
+			}
+
+			for _, group := range groups {
+				builder.WriteString(text[last:group[0]])
+				builder.WriteString(redactor.replacement)
+				last = group[1]
+			}
+		}
+
+		builder.WriteString(text[last:])
+		text = builder.String()
+	}
+
+	return text
+}
+
+// SetRedactor sets redactor which removes sensitive data from records of
+// given logger and all it's children, nil redactor disables redaction.
+func (log *Log) SetRedactor(redactor *Redactor) {
+	log.mutex.Lock()
+	log.setOptions(optionRedactor, func(options *logOptions) {
+		options.redactor = redactor
+	})
+	log.mutex.Unlock()
+}
+
+// redact replaces Redactable values of record fields and redacts message,
+// prefix and fields of given record using redactor of given logger.
+func (log *Log) redact(record *Record) {
+	record.Fields = redactableFields(record.Fields)
+
+	redactor := log.getOptions().redactor
+	if redactor == nil {
+		return
+	}
+
+	record.Message = redactor.Redact(record.Message)
+	record.Prefix = redactor.Redact(record.Prefix)
+	record.Fields = redactor.RedactFields(record.Fields)
+}
+
+// redactableValues returns given values with Redactable values replaced by
+// their redacted representation, given values are not changed.
+func redactableValues(values []interface{}) []interface{} {
+	var redacted []interface{}
+	for index, value := range values {
+		redactable, ok := value.(Redactable)
+		if !ok {
+			continue
+		}
+
+		if redacted == nil {
+			redacted = append([]interface{}(nil), values...)
+		}
+
+		redacted[index] = redactable.Redacted()
+	}
+
+	if redacted == nil {
+		return values
+	}
+
+	return redacted
+}
+
+// redactableFields is the same as redactableValues, but for fields.
+func redactableFields(fields Fields) Fields {
+	var redacted Fields
+	for index, field := range fields {
+		redactable, ok := field.Value.(Redactable)
+		if !ok {
+			continue
+		}
+
+		if redacted == nil {
+			redacted = append(Fields(nil), fields...)
+		}
+
+		redacted[index].Value = redactable.Redacted()
+	}
+
+	if redacted == nil {
+		return fields
+	}
+
+	return redacted
+}
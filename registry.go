@@ -0,0 +1,306 @@
+package lorg
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// loggerRegistry keeps named loggers and level spec rules of the root logger
+// and all it's children.
+type loggerRegistry struct {
+	root    *Log
+	loggers map[string]*Log
+	rules   []levelRule
+	spec    string
+	mutex   *sync.Mutex
+}
+
+type levelRule struct {
+	pattern string
+	level   Level
+}
+
+func newLoggerRegistry(root *Log) *loggerRegistry {
+	return &loggerRegistry{
+		root:  root,
+		mutex: &sync.Mutex{},
+	}
+}
+
+// Named returns child logger with given dotted name which is appended to the
+// name of given logger, so log.Named("db").Named("pool") returns the same
+// logger as log.Named("db.pool"). Intermediate loggers are created as well,
+// so "db.pool" is a child of "db" and inherits it's level.
+//
+// Named loggers are kept in the registry shared by the root logger and all
+// it's children, so subsequent calls with the same name return the same
+// logger, which can be looked up using Lookup and configured using
+// SetLevelSpec.
+//
+// Named child inherits level, format, output, prefix and fields options.
+// Named children of loggers created by With or NewChild are not kept in the
+// registry, they are created from the registered logger with the same name
+// and have fields and options of given logger.
+func (log *Log) Named(name string) *Log {
+	named := log
+	for _, part := range strings.Split(name, ".") {
+		if part == "" {
+			continue
+		}
+
+		named = named.namedChild(part)
+	}
+
+	return named
+}
+
+// GetName returns dotted name of the logger or empty string if logger has
+// not been created using Named.
+func (log *Log) GetName() string {
+	return log.name
+}
+
+// Lookup returns named logger with given full dotted name or nil if such
+// logger has not been created.
+func (log *Log) Lookup(name string) *Log {
+	log.registry.mutex.Lock()
+	defer log.registry.mutex.Unlock()
+
+	return log.registry.loggers[name]
+}
+
+// SetLevelSpec sets levels of named loggers using given spec which is a
+// comma-separated list of name=level rules:
+//
+//	info,db=debug,db.*=trace,http=warning
+//
+// Rule with exact name sets level of the logger and it's children, rule
+// with name ending with ".*" sets level of all descendants of the logger,
+// rule "*" sets level of all named loggers and rule without name sets level
+// of the root logger. More specific rules override less specific ones.
+//
+// Levels set by spec are not overridden by SetLevel of parent loggers, named
+// loggers which are not matched by any rule inherit level of their parents.
+// Spec replaces rules of the previous spec and is applied to all loggers of
+// the registry, including loggers which are created later.
+func (log *Log) SetLevelSpec(spec string) error {
+	rules, err := parseLevelSpec(spec)
+	if err != nil {
+		return err
+	}
+
+	registry := log.registry
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	registry.spec = spec
+	registry.rules = nil
+
+	for _, rule := range rules {
+		if rule.pattern == "" {
+			registry.root.SetLevel(rule.level)
+			continue
+		}
+
+		registry.rules = append(registry.rules, rule)
+	}
+
+	names := make([]string, 0, len(registry.loggers))
+	for name := range registry.loggers {
+		names = append(names, name)
+	}
+
+	// parents should be configured before children, because level of parent
+	// is propagated to it's children
+	sort.Slice(names, func(i, j int) bool {
+		return strings.Count(names[i], ".") < strings.Count(names[j], ".")
+	})
+
+	for _, name := range names {
+		registry.apply(registry.loggers[name])
+	}
+
+	return nil
+}
+
+// SetLevelSpecFromEnv sets levels of named loggers using spec which is
+// specified by environment variable with given name, see SetLevelSpec.
+// Levels are not changed if variable is not set or empty.
+func (log *Log) SetLevelSpecFromEnv(name string) error {
+	value := os.Getenv(name)
+	if value == "" {
+		return nil
+	}
+
+	err := log.SetLevelSpec(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// LevelSpecFlag returns flag.Value which sets levels of named loggers using
+// SetLevelSpec:
+//
+//	flag.Var(log.LevelSpecFlag(), "log-levels", "levels of modules")
+func (log *Log) LevelSpecFlag() flag.Value {
+	return &levelSpecFlag{log: log}
+}
+
+type levelSpecFlag struct {
+	log *Log
+}
+
+func (value *levelSpecFlag) String() string {
+	if value.log == nil {
+		return ""
+	}
+
+	value.log.registry.mutex.Lock()
+	defer value.log.registry.mutex.Unlock()
+
+	return value.log.registry.spec
+}
+
+func (value *levelSpecFlag) Set(spec string) error {
+	return value.log.SetLevelSpec(spec)
+}
+
+func (log *Log) namedChild(name string) *Log {
+	registry := log.registry
+
+	registry.mutex.Lock()
+	owner := registry.root
+	if log.name != "" {
+		owner = registry.loggers[log.name]
+	}
+	registry.mutex.Unlock()
+
+	// only the root and named loggers keep named children in the registry,
+	// so loggers created by With or NewChild use named child of the
+	// registered logger with their own fields and options
+	if owner != log {
+		child := owner.namedChild(name).WithFields(log.fields)
+		child.prefix = log.prefix
+		child.shiftIndent = log.shiftIndent
+		child.exiter = log.exiter
+		child.callerSkip = log.callerSkip
+
+		return child
+	}
+
+	if log.name != "" {
+		name = log.name + "." + name
+	}
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	if child, ok := registry.loggers[name]; ok {
+		return child
+	}
+
+	child := log.NewChild()
+	child.SetPrefix(log.prefix)
+	child.SetShiftIndent(log.shiftIndent)
+	child.SetExiter(log.exiter)
+	child.name = name
+	child.parent = log
+
+	if registry.loggers == nil {
+		registry.loggers = map[string]*Log{}
+	}
+
+	registry.loggers[name] = child
+
+	registry.apply(child)
+
+	return child
+}
+
+// apply sets level of given named logger using the most specific matching
+// rule or level of it's parent if there are no matching rules, registry
+// mutex should be locked.
+func (registry *loggerRegistry) apply(log *Log) {
+	level, pinned := registry.match(log.name)
+	if !pinned {
+		level = log.parent.GetLevel()
+	}
+
+	log.mutex.Lock()
+	log.pinned = pinned
+	log.setLevel(level)
+	log.mutex.Unlock()
+}
+
+func (registry *loggerRegistry) match(name string) (Level, bool) {
+	var (
+		level       Level
+		specificity = -1
+	)
+
+	for _, rule := range registry.rules {
+		ruleSpecificity := matchLevelRule(rule.pattern, name)
+		if ruleSpecificity > specificity {
+			level = rule.level
+			specificity = ruleSpecificity
+		}
+	}
+
+	return level, specificity >= 0
+}
+
+// matchLevelRule returns specificity of given pattern for given name or -1
+// if pattern doesn't match name. Exact names are more specific than
+// wildcards and longer wildcards are more specific than shorter ones.
+func matchLevelRule(pattern string, name string) int {
+	switch {
+	case pattern == name:
+		return 2 * (strings.Count(name, ".") + 1)
+
+	case pattern == "*":
+		return 0
+
+	case strings.HasSuffix(pattern, ".*") &&
+		strings.HasPrefix(name, pattern[:len(pattern)-1]):
+		return 2*strings.Count(pattern, ".") + 1
+	}
+
+	return -1
+}
+
+func parseLevelSpec(spec string) ([]levelRule, error) {
+	var rules []levelRule
+
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		pattern, value, found := strings.Cut(item, "=")
+		if !found {
+			pattern, value = "", item
+		}
+
+		pattern = strings.TrimSpace(pattern)
+		if found && pattern == "" {
+			return nil, fmt.Errorf("logger name is not specified: %q", item)
+		}
+
+		level, err := ParseLevel(value)
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, levelRule{pattern: pattern, level: level})
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,122 @@
+package lorg
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLog_Named_ReturnsSameLoggerForSameName(t *testing.T) {
+	test := assert.New(t)
+
+	log := NewLog()
+	log.SetPrefix("app ")
+
+	pool := log.Named("db.pool")
+
+	test.Equal("db.pool", pool.GetName())
+	test.Equal("app ", pool.prefix)
+	test.True(pool == log.Named("db").Named("pool"))
+	test.True(pool == log.With("a", 1).Lookup("db.pool"))
+	test.True(log.Lookup("db") == pool.parent)
+	test.Nil(log.Lookup("http"))
+}
+
+func TestLog_Named_KeepsFieldsOfLoggersCreatedByWith(t *testing.T) {
+	test := assert.New(t)
+
+	var buffer bytes.Buffer
+
+	log := NewLog()
+	log.SetOutput(&buffer)
+	log.SetFormat(NewFormat(`${prefix}%s${fields}`))
+
+	log.With("req", 1).Named("db").Info("query")
+	log.With("req", 2).Named("db").Info("query")
+	log.Named("db").Info("query")
+
+	child := log.NewChildWithPrefix("child")
+	child.Named("db").Info("query")
+
+	test.Equal(
+		"query req=1\n"+
+			"query req=2\n"+
+			"query\n"+
+			"child query\n",
+		buffer.String(),
+	)
+
+	test.Equal("db", log.With("req", 3).Named("db").GetName())
+	test.Empty(log.Lookup("db").fields)
+
+	test.NoError(log.SetLevelSpec("db=debug"))
+	test.Equal(LevelDebug, log.With("req", 4).Named("db").GetLevel())
+}
+
+func TestLog_SetLevelSpec_SetsLevelsOfNamedLoggers(t *testing.T) {
+	test := assert.New(t)
+
+	log := NewLog()
+
+	var (
+		database = log.Named("db")
+		pool     = log.Named("db.pool")
+		query    = pool.With("query", 1)
+		server   = log.Named("http.server")
+		cache    = log.Named("cache")
+	)
+
+	test.NoError(log.SetLevelSpec("warn, db=debug, db.*=trace, http=error"))
+
+	test.Equal(LevelWarning, log.GetLevel())
+	test.Equal(LevelDebug, database.GetLevel())
+	test.Equal(LevelTrace, pool.GetLevel())
+	test.Equal(LevelTrace, query.GetLevel())
+	test.Equal(LevelError, server.GetLevel())
+	test.Equal(LevelWarning, cache.GetLevel())
+
+	// pinned levels are not overridden by parents
+	log.SetLevel(LevelInfo)
+	test.Equal(LevelInfo, cache.GetLevel())
+	test.Equal(LevelDebug, database.GetLevel())
+	test.Equal(LevelTrace, pool.GetLevel())
+
+	// loggers which are created later are configured by spec too
+	test.Equal(LevelTrace, log.Named("db.conn").GetLevel())
+	test.Equal(LevelError, log.Named("http.client").GetLevel())
+
+	// new spec releases loggers which are not matched anymore
+	test.NoError(log.SetLevelSpec("*=error,db.pool=info"))
+	test.Equal(LevelInfo, log.GetLevel())
+	test.Equal(LevelError, database.GetLevel())
+	test.Equal(LevelInfo, pool.GetLevel())
+	test.Equal(LevelError, cache.GetLevel())
+
+	test.NoError(log.SetLevelSpec(""))
+	log.SetLevel(LevelDebug)
+	test.Equal(LevelDebug, pool.GetLevel())
+
+	test.Error(log.SetLevelSpec("db=verbose"))
+	test.Error(log.SetLevelSpec("=info"))
+}
+
+func TestLog_LevelSpecFlag_SetsLevelSpec(t *testing.T) {
+	test := assert.New(t)
+
+	log := NewLog()
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.Var(log.LevelSpecFlag(), "log-levels", "")
+
+	test.NoError(flags.Parse([]string{"--log-levels=db=trace"}))
+	test.Equal(LevelTrace, log.Named("db").GetLevel())
+	test.Equal("db=trace", flags.Lookup("log-levels").Value.String())
+
+	t.Setenv("LORG_TEST_LEVELS", "db=error")
+	test.NoError(log.SetLevelSpecFromEnv("LORG_TEST_LEVELS"))
+	test.Equal(LevelError, log.Named("db").GetLevel())
+}
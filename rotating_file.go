@@ -0,0 +1,529 @@
+package lorg
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// RotateHourly can be passed to RotatingFile.SetInterval for rotating
+	// file at the beginning of every hour.
+	RotateHourly = time.Hour
+
+	// RotateDaily can be passed to RotatingFile.SetInterval for rotating
+	// file at the local midnight.
+	RotateDaily = 24 * time.Hour
+
+	// RotatingFileDefaultBackupLayout is the time layout which will be used
+	// for names of backup files if layout is not specified.
+	RotatingFileDefaultBackupLayout = "2006-01-02T15-04-05.000"
+)
+
+// ensure that RotatingFile implements SmartOutput interface.
+var _ SmartOutput = (*RotatingFile)(nil)
+
+// RotatingFile is the SmartOutput which writes log records into a file and
+// rotates it by size, by wall-clock interval or both.
+//
+// Rotated file is renamed to the backup file named as the original file
+// with appended rotation time formatted using backup layout:
+// app.log.2016-01-02T09-21-44.000, backup files can be compressed using
+// gzip in background.
+//
+// RotatingFile is safe for concurrent use.
+//
+// Do not instantiate RotatingFile instance without using NewRotatingFile.
+type RotatingFile struct {
+	path string
+	file *os.File
+	size int64
+	next time.Time
+
+	maxSize      int64
+	interval     time.Duration
+	maxBackups   int
+	backupLayout string
+	compress     bool
+
+	mutex       *sync.Mutex
+	backupMutex *sync.Mutex
+	compressing *sync.WaitGroup
+	signals     chan os.Signal
+	done        chan struct{}
+
+	// for test purposes
+	now func() time.Time
+}
+
+// NewRotatingFile opens or creates file with given path in append mode and
+// returns RotatingFile which doesn't rotate file until rotation options
+// are set using SetMaxSize or SetInterval.
+func NewRotatingFile(path string) (*RotatingFile, error) {
+	rotating := &RotatingFile{
+		path:         path,
+		backupLayout: RotatingFileDefaultBackupLayout,
+		mutex:        &sync.Mutex{},
+		backupMutex:  &sync.Mutex{},
+		compressing:  &sync.WaitGroup{},
+		now:          time.Now,
+	}
+
+	err := rotating.open()
+	if err != nil {
+		return nil, err
+	}
+
+	return rotating, nil
+}
+
+// SetMaxSize sets maximum size of file in bytes, file will be rotated before
+// writing data which makes file larger than given size. Zero size disables
+// rotation by size.
+func (rotating *RotatingFile) SetMaxSize(size int64) *RotatingFile {
+	rotating.mutex.Lock()
+	rotating.maxSize = size
+	rotating.mutex.Unlock()
+
+	return rotating
+}
+
+// SetInterval sets wall-clock interval of rotation, for example RotateHourly
+// or RotateDaily. Intervals which are multiple of day are aligned to the local
+// midnight, other intervals are aligned to the multiple of interval since
+// zero time. Zero interval disables rotation by time.
+func (rotating *RotatingFile) SetInterval(
+	interval time.Duration,
+) *RotatingFile {
+	rotating.mutex.Lock()
+	rotating.interval = interval
+	rotating.next = nextRotation(rotating.now(), interval)
+	rotating.mutex.Unlock()
+
+	return rotating
+}
+
+// SetMaxBackups sets amount of backup files which will be kept, the oldest
+// backup files will be removed after rotation. Zero amount forces
+// RotatingFile to keep all backup files.
+func (rotating *RotatingFile) SetMaxBackups(backups int) *RotatingFile {
+	rotating.mutex.Lock()
+	rotating.maxBackups = backups
+	rotating.mutex.Unlock()
+
+	return rotating
+}
+
+// SetBackupLayout sets time layout which will be used for names of backup
+// files, see RotatingFileDefaultBackupLayout.
+func (rotating *RotatingFile) SetBackupLayout(layout string) *RotatingFile {
+	rotating.mutex.Lock()
+	rotating.backupLayout = layout
+	rotating.mutex.Unlock()
+
+	return rotating
+}
+
+// SetCompress enables or disables gzip compression of backup files,
+// compression is running in background, so Close should be called for
+// waiting for compression to complete.
+func (rotating *RotatingFile) SetCompress(compress bool) *RotatingFile {
+	rotating.mutex.Lock()
+	rotating.compress = compress
+	rotating.mutex.Unlock()
+
+	return rotating
+}
+
+// ReopenOnSignal forces RotatingFile to reopen file after receiving any of
+// given signals or SIGHUP if signals are not specified, so file can be
+// rotated by external tools like logrotate. File is not reopened on systems
+// without SIGHUP if signals are not specified.
+func (rotating *RotatingFile) ReopenOnSignal(
+	signals ...os.Signal,
+) *RotatingFile {
+	if len(signals) == 0 {
+		signals = defaultReopenSignals
+	}
+
+	// signal.Notify relays all signals if no signals are given
+	if len(signals) == 0 {
+		return rotating
+	}
+
+	rotating.mutex.Lock()
+	defer rotating.mutex.Unlock()
+
+	if rotating.signals != nil {
+		signal.Notify(rotating.signals, signals...)
+		return rotating
+	}
+
+	rotating.signals = make(chan os.Signal, 1)
+	rotating.done = make(chan struct{})
+
+	signal.Notify(rotating.signals, signals...)
+
+	go func(signals chan os.Signal, done chan struct{}) {
+		for {
+			select {
+			case <-signals:
+				err := rotating.Reopen()
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "can't reopen log file: %s\n", err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}(rotating.signals, rotating.done)
+
+	return rotating
+}
+
+// Write writes given data into the file, rotating file before writing if
+// it's required.
+func (rotating *RotatingFile) Write(data []byte) (int, error) {
+	rotating.mutex.Lock()
+	defer rotating.mutex.Unlock()
+
+	if rotating.file == nil {
+		return 0, fmt.Errorf("log file %s is closed", rotating.path)
+	}
+
+	if rotating.shouldRotate(len(data)) {
+		err := rotating.rotate()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	written, err := rotating.file.Write(data)
+	rotating.size += int64(written)
+
+	return written, err
+}
+
+// WriteWithLevel writes given data into the file, level is ignored.
+func (rotating *RotatingFile) WriteWithLevel(
+	data []byte, _ Level,
+) (int, error) {
+	return rotating.Write(data)
+}
+
+// Rotate rotates file regardless of rotation options.
+func (rotating *RotatingFile) Rotate() error {
+	rotating.mutex.Lock()
+	defer rotating.mutex.Unlock()
+
+	if rotating.file == nil {
+		return fmt.Errorf("log file %s is closed", rotating.path)
+	}
+
+	return rotating.rotate()
+}
+
+// Reopen closes and opens file again, it's usable after file has been moved
+// by external tools.
+func (rotating *RotatingFile) Reopen() error {
+	rotating.mutex.Lock()
+	defer rotating.mutex.Unlock()
+
+	if rotating.file == nil {
+		return fmt.Errorf("log file %s is closed", rotating.path)
+	}
+
+	err := rotating.file.Close()
+	if err != nil {
+		return err
+	}
+
+	return rotating.open()
+}
+
+// Close stops handling signals, waits for background compression and closes
+// the file.
+func (rotating *RotatingFile) Close() error {
+	rotating.mutex.Lock()
+
+	if rotating.signals != nil {
+		signal.Stop(rotating.signals)
+		close(rotating.done)
+		rotating.signals = nil
+	}
+
+	var err error
+	if rotating.file != nil {
+		err = rotating.file.Close()
+		rotating.file = nil
+	}
+
+	rotating.mutex.Unlock()
+
+	rotating.compressing.Wait()
+
+	return err
+}
+
+func (rotating *RotatingFile) open() error {
+	file, err := os.OpenFile(
+		rotating.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644,
+	)
+	if err != nil {
+		return err
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	rotating.file = file
+	rotating.size = stat.Size()
+
+	return nil
+}
+
+func (rotating *RotatingFile) shouldRotate(length int) bool {
+	if rotating.maxSize > 0 && rotating.size > 0 &&
+		rotating.size+int64(length) > rotating.maxSize {
+		return true
+	}
+
+	if rotating.interval > 0 && !rotating.now().Before(rotating.next) {
+		return true
+	}
+
+	return false
+}
+
+func (rotating *RotatingFile) rotate() error {
+	now := rotating.now()
+
+	err := rotating.file.Close()
+	if err != nil {
+		return err
+	}
+
+	backup := rotating.path + "." + now.Format(rotating.backupLayout)
+	for index := 1; exists(backup) || exists(backup+".gz"); index++ {
+		backup = rotating.path + "." + now.Format(rotating.backupLayout) +
+			"." + strconv.Itoa(index)
+	}
+
+	renameErr := os.Rename(rotating.path, backup)
+
+	// file should be opened even if it can't be renamed, otherwise all
+	// following records will be lost.
+	err = rotating.open()
+	if err != nil {
+		return err
+	}
+
+	if rotating.interval > 0 {
+		rotating.next = nextRotation(now, rotating.interval)
+	}
+
+	if renameErr != nil {
+		return renameErr
+	}
+
+	if rotating.compress {
+		rotating.compressing.Add(1)
+		go func(maxBackups int, layout string) {
+			defer rotating.compressing.Done()
+
+			// backups should not be removed while backup is compressed
+			rotating.backupMutex.Lock()
+			err := compressFile(backup)
+			rotating.backupMutex.Unlock()
+
+			if err != nil {
+				fmt.Fprintf(
+					os.Stderr, "can't compress log file %s: %s\n", backup, err,
+				)
+			}
+
+			rotating.removeBackups(maxBackups, layout)
+		}(rotating.maxBackups, rotating.backupLayout)
+
+		return nil
+	}
+
+	return rotating.removeBackups(rotating.maxBackups, rotating.backupLayout)
+}
+
+// removeBackups removes the oldest backups if there are more backups than
+// given maximum, backup and it's compressed version are treated as one
+// backup. Only files which names are made using given backup layout are
+// treated as backups.
+func (rotating *RotatingFile) removeBackups(
+	maxBackups int, layout string,
+) error {
+	if maxBackups <= 0 {
+		return nil
+	}
+
+	rotating.backupMutex.Lock()
+	defer rotating.backupMutex.Unlock()
+
+	paths, err := filepath.Glob(escapeGlob(rotating.path) + ".*")
+	if err != nil {
+		return err
+	}
+
+	type backup struct {
+		name     string
+		modified time.Time
+	}
+
+	backups := map[string]*backup{}
+	for _, path := range paths {
+		stat, err := os.Stat(path)
+		if err != nil || !stat.Mode().IsRegular() {
+			continue
+		}
+
+		name := strings.TrimSuffix(path, ".gz")
+		if !isBackupName(strings.TrimPrefix(name, rotating.path+"."), layout) {
+			continue
+		}
+
+		if _, ok := backups[name]; !ok {
+			backups[name] = &backup{name: name, modified: stat.ModTime()}
+		}
+	}
+
+	sorted := make([]*backup, 0, len(backups))
+	for _, backup := range backups {
+		sorted = append(sorted, backup)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].modified.Equal(sorted[j].modified) {
+			return sorted[i].name > sorted[j].name
+		}
+
+		return sorted[i].modified.After(sorted[j].modified)
+	})
+
+	for index := maxBackups; index < len(sorted); index++ {
+		for _, path := range []string{
+			sorted[index].name, sorted[index].name + ".gz",
+		} {
+			err := os.Remove(path)
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// isBackupName returns true if given suffix of backup file name is the
+// rotation time formatted using given layout with optional index:
+// 2016-01-02T09-21-44.000 or 2016-01-02T09-21-44.000.1.
+func isBackupName(suffix string, layout string) bool {
+	if _, err := time.Parse(layout, suffix); err == nil {
+		return true
+	}
+
+	dot := strings.LastIndexByte(suffix, '.')
+	if dot < 0 {
+		return false
+	}
+
+	if _, err := strconv.Atoi(suffix[dot+1:]); err != nil {
+		return false
+	}
+
+	_, err := time.Parse(layout, suffix[:dot])
+
+	return err == nil
+}
+
+func nextRotation(now time.Time, interval time.Duration) time.Time {
+	if interval <= 0 {
+		return time.Time{}
+	}
+
+	if interval%RotateDaily == 0 {
+		year, month, day := now.Date()
+		return time.Date(
+			year, month, day+int(interval/RotateDaily), 0, 0, 0, 0,
+			now.Location(),
+		)
+	}
+
+	return now.Truncate(interval).Add(interval)
+}
+
+func compressFile(path string) error {
+	source, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	err = writeGzip(path+".gz", source)
+
+	source.Close()
+
+	if err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+
+	return os.Remove(path)
+}
+
+func writeGzip(path string, source io.Reader) error {
+	target, err := os.OpenFile(
+		path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644,
+	)
+	if err != nil {
+		return err
+	}
+
+	defer target.Close()
+
+	writer := gzip.NewWriter(target)
+
+	_, err = io.Copy(writer, source)
+	if err != nil {
+		return err
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return err
+	}
+
+	return target.Close()
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+func escapeGlob(path string) string {
+	replacer := strings.NewReplacer(
+		`*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`,
+	)
+
+	if filepath.Separator == '\\' {
+		return path
+	}
+
+	return replacer.Replace(strings.ReplaceAll(path, `\`, `\\`))
+}
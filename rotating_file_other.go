@@ -0,0 +1,9 @@
+//go:build !unix
+
+package lorg
+
+import "os"
+
+// defaultReopenSignals are signals which are used by ReopenOnSignal if
+// signals are not specified, SIGHUP is not available on such systems.
+var defaultReopenSignals []os.Signal
@@ -0,0 +1,212 @@
+package lorg
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotatingFile_RotatesBySize(t *testing.T) {
+	test := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	rotating, err := NewRotatingFile(path)
+	test.NoError(err)
+
+	now := time.Date(2016, 1, 2, 9, 21, 44, 0, time.Local)
+	rotating.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	rotating.SetMaxSize(10).SetMaxBackups(2)
+
+	log := NewLog()
+	log.SetOutput(rotating)
+	log.SetFormat(NewFormat(`%s`))
+
+	for _, text := range []string{
+		"aaaa", "bbbb", "cccc", "dddd", "eeee", "ffff", "gggg",
+	} {
+		log.Info(text)
+	}
+
+	test.NoError(rotating.Close())
+
+	test.Equal("gggg\n", readFile(t, path))
+	test.Equal(
+		[]string{
+			path + ".2016-01-02T09-21-46.000",
+			path + ".2016-01-02T09-21-47.000",
+		},
+		globBackups(t, path),
+	)
+	test.Equal(
+		"eeee\nffff\n", readFile(t, path+".2016-01-02T09-21-47.000"),
+	)
+}
+
+func TestRotatingFile_SetMaxBackups_KeepsOtherFiles(t *testing.T) {
+	test := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	for _, name := range []string{
+		path + ".keepme", path + ".2016-01-02.gz", path + ".1",
+	} {
+		test.NoError(os.WriteFile(name, []byte("user file\n"), 0644))
+	}
+
+	rotating, err := NewRotatingFile(path)
+	test.NoError(err)
+
+	now := time.Date(2016, 1, 2, 9, 21, 44, 0, time.Local)
+	rotating.now = func() time.Time {
+		return now
+	}
+
+	rotating.SetMaxSize(5).SetMaxBackups(1)
+
+	for _, text := range []string{"aaaa\n", "bbbb\n", "cccc\n"} {
+		_, err := rotating.WriteWithLevel([]byte(text), LevelInfo)
+		test.NoError(err)
+	}
+
+	test.NoError(rotating.Close())
+
+	test.Equal(
+		[]string{
+			path + ".1",
+			path + ".2016-01-02.gz",
+			path + ".2016-01-02T09-21-44.000.1",
+			path + ".keepme",
+		},
+		globBackups(t, path),
+	)
+}
+
+func TestRotatingFile_RotatesByIntervalAndCompresses(t *testing.T) {
+	test := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	rotating, err := NewRotatingFile(path)
+	test.NoError(err)
+
+	now := time.Date(2016, 1, 2, 9, 59, 0, 0, time.Local)
+	rotating.now = func() time.Time {
+		return now
+	}
+
+	rotating.SetInterval(RotateHourly).SetCompress(true)
+
+	_, err = rotating.WriteWithLevel([]byte("first\n"), LevelInfo)
+	test.NoError(err)
+
+	now = now.Add(time.Minute)
+
+	_, err = rotating.WriteWithLevel([]byte("second\n"), LevelInfo)
+	test.NoError(err)
+
+	test.NoError(rotating.Close())
+
+	backup := path + ".2016-01-02T10-00-00.000.gz"
+	test.Equal([]string{backup}, globBackups(t, path))
+	test.Equal("second\n", readFile(t, path))
+
+	file, err := os.Open(backup)
+	test.NoError(err)
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	test.NoError(err)
+
+	data, err := io.ReadAll(reader)
+	test.NoError(err)
+	test.Equal("first\n", string(data))
+}
+
+func TestRotatingFile_Reopen_CreatesMovedFile(t *testing.T) {
+	test := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	rotating, err := NewRotatingFile(path)
+	test.NoError(err)
+
+	_, err = rotating.Write([]byte("before\n"))
+	test.NoError(err)
+
+	test.NoError(os.Rename(path, path+".1"))
+	test.NoError(rotating.Reopen())
+
+	_, err = rotating.Write([]byte("after\n"))
+	test.NoError(err)
+	test.NoError(rotating.Close())
+
+	test.Equal("before\n", readFile(t, path+".1"))
+	test.Equal("after\n", readFile(t, path))
+}
+
+func TestRotatingFile_ConcurrentWrites(t *testing.T) {
+	test := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	rotating, err := NewRotatingFile(path)
+	test.NoError(err)
+
+	rotating.SetMaxSize(100)
+
+	output := NewOutput(rotating)
+
+	group := &sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		group.Add(1)
+		go func() {
+			defer group.Done()
+			for j := 0; j < 100; j++ {
+				_, err := output.WriteWithLevel([]byte("0123456789\n"), LevelInfo)
+				test.NoError(err)
+			}
+		}()
+	}
+
+	group.Wait()
+	test.NoError(rotating.Close())
+
+	size := 0
+	for _, backup := range append(globBackups(t, path), path) {
+		size += len(readFile(t, backup))
+	}
+
+	test.Equal(8*100*11, size)
+}
+
+func readFile(t *testing.T, path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func globBackups(t *testing.T, path string) []string {
+	backups, err := filepath.Glob(path + ".*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(backups)
+
+	return backups
+}
@@ -0,0 +1,12 @@
+//go:build unix
+
+package lorg
+
+import (
+	"os"
+	"syscall"
+)
+
+// defaultReopenSignals are signals which are used by ReopenOnSignal if
+// signals are not specified.
+var defaultReopenSignals = []os.Signal{syscall.SIGHUP}
@@ -0,0 +1,365 @@
+package lorg
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// SamplerDefaultSummaryInterval is the interval of summary records if
+	// it's not specified using Sampler.SetSummaryInterval.
+	SamplerDefaultSummaryInterval = 10 * time.Second
+
+	// samplerMaxCounters is the amount of counters after which Sampler
+	// removes counters which are not used in the current tick.
+	samplerMaxCounters = 4096
+)
+
+// SampleKey describes which records are considered similar by Sampler, keys
+// can be combined: SampleByTemplate | SampleByCaller.
+type SampleKey int
+
+const (
+	// SampleByTemplate groups records by format passed to Printf-like
+	// logging functions or by message for other logging functions.
+	SampleByTemplate SampleKey = 1 << iota
+
+	// SampleByCaller groups records by place where logging function has
+	// been called.
+	SampleByCaller
+)
+
+// Sampler limits amount of records which are written by Log, so hot loop
+// can't flood output with identical records. Records can be sampled by
+// "first N per tick then every Mth" policy which is applied to every group
+// of similar records and can be limited by token bucket which is applied to
+// all records of the level.
+//
+// Suppressed records are counted and summary record like "suppressed 12,345
+// similar messages: disk is full" is written periodically for every group
+// of similar records with the level of suppressed records through the
+// logger which suppressed them.
+//
+// Sampler is safe for concurrent use and can be shared between loggers,
+// children share sampler of the parent.
+//
+// Do not instantiate Sampler instance without using NewSampler.
+type Sampler struct {
+	key      SampleKey
+	policies [LevelTrace + 1]*samplePolicy
+	buckets  [LevelTrace + 1]*tokenBucket
+	counters map[sampleKey]*sampleCounter
+	interval time.Duration
+	timer    *time.Timer
+	mutex    *sync.Mutex
+
+	// for test purposes
+	now func() time.Time
+}
+
+type samplePolicy struct {
+	tick       time.Duration
+	first      int
+	thereafter int
+}
+
+type tokenBucket struct {
+	rate    float64
+	burst   float64
+	tokens  float64
+	updated time.Time
+}
+
+type sampleKey struct {
+	level    Level
+	template string
+	pc       uintptr
+}
+
+type sampleCounter struct {
+	tickStart time.Time
+	count     int
+
+	suppressed int
+	log        *Log
+	template   string
+	record     Record
+}
+
+// NewSampler creates Sampler which doesn't suppress records until policies
+// or rate limits are set, similar records are grouped by SampleByTemplate
+// and SampleByCaller.
+func NewSampler() *Sampler {
+	return &Sampler{
+		key:      SampleByTemplate | SampleByCaller,
+		counters: map[sampleKey]*sampleCounter{},
+		interval: SamplerDefaultSummaryInterval,
+		mutex:    &sync.Mutex{},
+		now:      time.Now,
+	}
+}
+
+// SetSampling sets policy for records of given level: first records of
+// every group of similar records are written during given tick, then only
+// every thereafter record is written. Zero thereafter suppresses all records
+// after first ones.
+//
+//	sampler.SetSampling(lorg.LevelWarning, time.Second, 100, 1000)
+func (sampler *Sampler) SetSampling(
+	level Level, tick time.Duration, first, thereafter int,
+) *Sampler {
+	sampler.mutex.Lock()
+
+	if level >= LevelFatal && level <= LevelTrace {
+		sampler.policies[level] = &samplePolicy{
+			tick:       tick,
+			first:      first,
+			thereafter: thereafter,
+		}
+	}
+
+	sampler.mutex.Unlock()
+
+	return sampler
+}
+
+// SetRateLimit limits amount of written records of given level to rate
+// records per second with bursts of given size using token bucket, limit is
+// shared by all records of the level.
+func (sampler *Sampler) SetRateLimit(
+	level Level, rate float64, burst int,
+) *Sampler {
+	sampler.mutex.Lock()
+
+	if level >= LevelFatal && level <= LevelTrace {
+		sampler.buckets[level] = &tokenBucket{
+			rate:    rate,
+			burst:   float64(burst),
+			tokens:  float64(burst),
+			updated: sampler.now(),
+		}
+	}
+
+	sampler.mutex.Unlock()
+
+	return sampler
+}
+
+// SetKey sets which records are considered similar.
+func (sampler *Sampler) SetKey(key SampleKey) *Sampler {
+	sampler.mutex.Lock()
+	sampler.key = key
+	sampler.counters = map[sampleKey]*sampleCounter{}
+	sampler.mutex.Unlock()
+
+	return sampler
+}
+
+// SetSummaryInterval sets interval of summary records, zero interval
+// disables summary records.
+func (sampler *Sampler) SetSummaryInterval(interval time.Duration) *Sampler {
+	sampler.mutex.Lock()
+	sampler.interval = interval
+	sampler.mutex.Unlock()
+
+	return sampler
+}
+
+// Summarize writes summary records of suppressed records immediately
+// instead of waiting for summary interval, for example, before exiting.
+func (sampler *Sampler) Summarize() {
+	sampler.mutex.Lock()
+
+	if sampler.timer != nil {
+		sampler.timer.Stop()
+		sampler.timer = nil
+	}
+
+	type summary struct {
+		log    *Log
+		record Record
+	}
+
+	var summaries []summary
+	for _, counter := range sampler.counters {
+		if counter.suppressed == 0 {
+			continue
+		}
+
+		record := counter.record
+		record.Time = sampler.now()
+		record.Message = "suppressed " + formatCount(counter.suppressed) +
+			" similar messages: " + counter.template
+
+		summaries = append(summaries, summary{
+			log:    counter.log,
+			record: record,
+		})
+
+		counter.suppressed = 0
+		counter.log = nil
+	}
+
+	sampler.mutex.Unlock()
+
+	for _, summary := range summaries {
+		err := summary.log.emitRecord(&summary.record)
+		if err != nil {
+			summary.log.handleError(
+				fmt.Errorf("failed to write to log: %w", err),
+			)
+		}
+	}
+}
+
+// sample returns false if given record of given logger should be
+// suppressed, template is the format of the record.
+func (sampler *Sampler) sample(log *Log, record *Record, template string) bool {
+	level := record.Level
+	if level < LevelFatal || level > LevelTrace {
+		return true
+	}
+
+	sampler.mutex.Lock()
+	defer sampler.mutex.Unlock()
+
+	policy := sampler.policies[level]
+	bucket := sampler.buckets[level]
+	if policy == nil && bucket == nil {
+		return true
+	}
+
+	now := sampler.now()
+
+	key := sampleKey{level: level}
+	if sampler.key&SampleByTemplate != 0 {
+		key.template = template
+	}
+
+	if sampler.key&SampleByCaller != 0 {
+		key.pc = record.PC
+	}
+
+	counter := sampler.counters[key]
+	if counter == nil {
+		if len(sampler.counters) >= samplerMaxCounters {
+			sampler.removeStaleCounters(now)
+		}
+
+		counter = &sampleCounter{}
+		sampler.counters[key] = counter
+	}
+
+	if policy != nil && !policy.allow(counter, now) {
+		sampler.suppress(log, record, template, counter)
+		return false
+	}
+
+	if bucket != nil && !bucket.allow(now) {
+		sampler.suppress(log, record, template, counter)
+		return false
+	}
+
+	return true
+}
+
+// suppress counts suppressed record and schedules summary, sampler mutex
+// should be locked.
+func (sampler *Sampler) suppress(
+	log *Log, record *Record, template string, counter *sampleCounter,
+) {
+	counter.suppressed++
+	counter.log = log
+	counter.template = template
+	counter.record = Record{
+		Level:    record.Level,
+		Prefix:   record.Prefix,
+		Fields:   log.fields,
+		PC:       record.PC,
+		File:     record.File,
+		Line:     record.Line,
+		Function: record.Function,
+	}
+
+	if sampler.timer == nil && sampler.interval > 0 {
+		sampler.timer = time.AfterFunc(sampler.interval, sampler.Summarize)
+	}
+}
+
+// removeStaleCounters removes counters which have no suppressed records and
+// which tick is over, sampler mutex should be locked.
+func (sampler *Sampler) removeStaleCounters(now time.Time) {
+	for key, counter := range sampler.counters {
+		if counter.suppressed != 0 {
+			continue
+		}
+
+		policy := sampler.policies[key.level]
+		if policy == nil || now.Sub(counter.tickStart) >= policy.tick {
+			delete(sampler.counters, key)
+		}
+	}
+}
+
+func (policy *samplePolicy) allow(counter *sampleCounter, now time.Time) bool {
+	if now.Sub(counter.tickStart) >= policy.tick {
+		counter.tickStart = now
+		counter.count = 0
+	}
+
+	counter.count++
+
+	if counter.count <= policy.first {
+		return true
+	}
+
+	return policy.thereafter > 0 &&
+		(counter.count-policy.first)%policy.thereafter == 0
+}
+
+func (bucket *tokenBucket) allow(now time.Time) bool {
+	if elapsed := now.Sub(bucket.updated); elapsed > 0 {
+		bucket.tokens += elapsed.Seconds() * bucket.rate
+		if bucket.tokens > bucket.burst {
+			bucket.tokens = bucket.burst
+		}
+
+		bucket.updated = now
+	}
+
+	if bucket.tokens < 1 {
+		return false
+	}
+
+	bucket.tokens--
+
+	return true
+}
+
+// SetSampler sets sampler which limits amount of records written by given
+// logger and all it's children, nil sampler disables sampling.
+func (log *Log) SetSampler(sampler *Sampler) {
+	log.mutex.Lock()
+	log.setOptions(optionSampler, func(options *logOptions) {
+		options.sampler = sampler
+	})
+	log.mutex.Unlock()
+}
+
+// formatCount formats given number with thousands separators: 12,345.
+func formatCount(count int) string {
+	digits := strconv.Itoa(count)
+
+	var formatted []byte
+	for index := range digits {
+		if index > 0 && (len(digits)-index)%3 == 0 {
+			formatted = append(formatted, ',')
+		}
+
+		formatted = append(formatted, digits[index])
+	}
+
+	return string(formatted)
+}
@@ -0,0 +1,245 @@
+package lorg
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampler_SetSampling_WritesFirstThenEveryNth(t *testing.T) {
+	test := assert.New(t)
+
+	now := time.Now()
+
+	sampler := NewSampler().
+		SetSampling(LevelWarning, time.Second, 2, 3).
+		SetSummaryInterval(0)
+	sampler.now = func() time.Time {
+		return now
+	}
+
+	log := NewLog()
+	buffer := &bytes.Buffer{}
+	log.SetOutput(buffer)
+	log.SetFormat(NewFormat(`${level} %s${fields}`))
+	log.SetSampler(sampler)
+
+	child := log.With("id", 1)
+
+	for i := 1; i <= 8; i++ {
+		child.Warningf("disk is full #%d", i)
+	}
+
+	log.Info("not sampled")
+
+	now = now.Add(time.Second)
+	child.Warningf("disk is full #%d", 9)
+
+	sampler.Summarize()
+
+	test.Equal(
+		"WARNING disk is full #1 id=1\n"+
+			"WARNING disk is full #2 id=1\n"+
+			"WARNING disk is full #5 id=1\n"+
+			"WARNING disk is full #8 id=1\n"+
+			"INFO not sampled\n"+
+			"WARNING disk is full #9 id=1\n"+
+			"WARNING suppressed 4 similar messages: disk is full #%d id=1\n",
+		buffer.String(),
+	)
+}
+
+func TestLog_SetSampler_IsSharedWithExistingChildren(t *testing.T) {
+	test := assert.New(t)
+
+	log := NewLog()
+	buffer := &bytes.Buffer{}
+	log.SetOutput(buffer)
+	log.SetFormat(NewFormat(`${prefix}%s`))
+
+	child := log.NewChildWithPrefix("child")
+
+	sampler := NewSampler().
+		SetSampling(LevelInfo, time.Hour, 1, 0).
+		SetSummaryInterval(0)
+	log.SetSampler(sampler)
+
+	for i := 0; i < 3; i++ {
+		child.Info("retrying")
+	}
+
+	test.Equal("child retrying\n", buffer.String())
+}
+
+func TestLog_SetSampler_IsSafeForConcurrentLogging(t *testing.T) {
+	log := NewLog()
+	log.SetOutput(&bytes.Buffer{})
+
+	child := log.Named("db")
+
+	sampler := NewSampler().
+		SetSampling(LevelInfo, time.Hour, 1, 0).
+		SetSummaryInterval(0)
+
+	group := &sync.WaitGroup{}
+	group.Add(2)
+
+	go func() {
+		defer group.Done()
+
+		for i := 0; i < 1000; i++ {
+			log.SetSampler(sampler)
+			log.SetSampler(nil)
+		}
+	}()
+
+	go func() {
+		defer group.Done()
+
+		for i := 0; i < 1000; i++ {
+			child.Info("concurrent")
+		}
+	}()
+
+	group.Wait()
+}
+
+func TestSampler_SetRateLimit_LimitsAllRecordsOfLevel(t *testing.T) {
+	test := assert.New(t)
+
+	now := time.Now()
+
+	sampler := NewSampler()
+	sampler.now = func() time.Time {
+		return now
+	}
+
+	sampler.SetRateLimit(LevelError, 2, 3).SetSummaryInterval(0)
+
+	log := NewLog()
+	buffer := &bytes.Buffer{}
+	log.SetOutput(buffer)
+	log.SetFormat(NewFormat(`%s`))
+	log.SetSampler(sampler)
+
+	for i := 0; i < 5; i++ {
+		log.Error("first")
+	}
+
+	// two tokens are added in a second
+	now = now.Add(time.Second)
+
+	for i := 0; i < 3; i++ {
+		log.Error("second")
+	}
+
+	test.Equal(
+		"first\nfirst\nfirst\nsecond\nsecond\n", buffer.String(),
+	)
+
+	sampler.Summarize()
+
+	test.Contains(buffer.String(), "suppressed 2 similar messages: first\n")
+	test.Contains(buffer.String(), "suppressed 1 similar messages: second\n")
+}
+
+func TestSampler_SetKey_GroupsRecordsByCaller(t *testing.T) {
+	test := assert.New(t)
+
+	sampler := NewSampler().
+		SetSampling(LevelInfo, time.Hour, 1, 0).
+		SetKey(SampleByCaller).
+		SetSummaryInterval(0)
+
+	log := NewLog()
+	buffer := &bytes.Buffer{}
+	log.SetOutput(buffer)
+	log.SetFormat(NewFormat(`%s`))
+	log.SetSampler(sampler)
+
+	for i := 0; i < 3; i++ {
+		log.Infof("loop %d", i)
+	}
+
+	log.Info("another caller")
+
+	test.Equal("loop 0\nanother caller\n", buffer.String())
+}
+
+func TestSampler_Summary_IsWrittenPeriodically(t *testing.T) {
+	test := assert.New(t)
+
+	sampler := NewSampler().
+		SetSampling(LevelWarning, time.Hour, 1, 0).
+		SetSummaryInterval(10 * time.Millisecond)
+
+	var (
+		buffer bytes.Buffer
+		mutex  sync.Mutex
+	)
+
+	log := NewLog()
+	log.SetOutput(writerFunc(func(data []byte) (int, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return buffer.Write(data)
+	}))
+	log.SetFormat(NewFormat(`%s`))
+	log.SetSampler(sampler)
+
+	var group sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		group.Add(1)
+		go func() {
+			defer group.Done()
+			for j := 0; j < 1000; j++ {
+				log.Warning("spam")
+			}
+		}()
+	}
+
+	group.Wait()
+
+	// summary can be written several times while goroutines are logging
+	suppressed := func() int {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		total := 0
+		for _, line := range strings.Split(buffer.String(), "\n") {
+			var count string
+			_, err := fmt.Sscanf(line, "suppressed %s similar", &count)
+			if err == nil {
+				number, _ := strconv.Atoi(strings.Replace(count, ",", "", -1))
+				total += number
+			}
+		}
+
+		return total
+	}
+
+	test.Eventually(func() bool {
+		return suppressed() == 3999
+	}, time.Second, time.Millisecond)
+}
+
+func TestFormatCount_SeparatesThousands(t *testing.T) {
+	test := assert.New(t)
+
+	test.Equal("0", formatCount(0))
+	test.Equal("999", formatCount(999))
+	test.Equal("12,345", formatCount(12345))
+	test.Equal("1,234,567", formatCount(1234567))
+}
+
+type writerFunc func(data []byte) (int, error)
+
+func (writer writerFunc) Write(data []byte) (int, error) {
+	return writer(data)
+}
@@ -17,49 +17,61 @@ func SetIndentLine(enabled bool) {
 }
 
 func Fatalf(format string, values ...interface{}) {
+	lorg.Helper()
 	logger.Fatalf(format, values...)
 }
 
 func Errorf(format string, values ...interface{}) {
+	lorg.Helper()
 	logger.Errorf(format, values...)
 }
 
 func Warningf(format string, values ...interface{}) {
+	lorg.Helper()
 	logger.Warningf(format, values...)
 }
 
 func Infof(format string, values ...interface{}) {
+	lorg.Helper()
 	logger.Infof(format, values...)
 }
 
 func Debugf(format string, values ...interface{}) {
+	lorg.Helper()
 	logger.Debugf(format, values...)
 }
 
 func Tracef(format string, values ...interface{}) {
+	lorg.Helper()
 	logger.Tracef(format, values...)
 }
 
 func Fatal(values ...interface{}) {
+	lorg.Helper()
 	logger.Fatal(values...)
 }
 
 func Error(values ...interface{}) {
+	lorg.Helper()
 	logger.Error(values...)
 }
 
 func Warning(values ...interface{}) {
+	lorg.Helper()
 	logger.Warning(values...)
 }
 
 func Info(values ...interface{}) {
+	lorg.Helper()
 	logger.Info(values...)
 }
 
 func Debug(values ...interface{}) {
+	lorg.Helper()
 	logger.Debug(values...)
 }
 
 func Trace(values ...interface{}) {
+	lorg.Helper()
 	logger.Trace(values...)
 }
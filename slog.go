@@ -0,0 +1,271 @@
+package lorg
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"time"
+)
+
+const (
+	// SlogLevelTrace is the slog level which corresponds to LevelTrace.
+	SlogLevelTrace = slog.LevelDebug - 4
+
+	// SlogLevelFatal is the slog level which corresponds to LevelFatal.
+	SlogLevelFatal = slog.LevelError + 4
+)
+
+// ensure that SlogHandler implements slog.Handler interface.
+var _ slog.Handler = (*SlogHandler)(nil)
+
+// SlogHandler is the slog.Handler which writes slog records using given Log,
+// so records are formatted by Log format and written to Log output.
+//
+// slog attributes are converted to Log fields, attributes of groups are
+// prefixed by group names separated by dots: "request.id".
+type SlogHandler struct {
+	log   *Log
+	group string
+}
+
+// NewSlogHandler creates SlogHandler which writes records to given log:
+//
+//	slog.SetDefault(slog.New(lorg.NewSlogHandler(log)))
+func NewSlogHandler(log *Log) *SlogHandler {
+	return &SlogHandler{log: log}
+}
+
+// Enabled reports whether given log level allows records with given slog
+// level.
+func (handler *SlogHandler) Enabled(
+	_ context.Context, level slog.Level,
+) bool {
+	return handler.log.GetLevel() >= LevelFromSlog(level)
+}
+
+// Handle writes given slog record to the log, fields returned by registered
+// context extractors are attached to the record.
+func (handler *SlogHandler) Handle(
+	ctx context.Context, slogRecord slog.Record,
+) error {
+	fields := contextFields(ctx)
+	slogRecord.Attrs(func(attr slog.Attr) bool {
+		fields = appendSlogAttr(fields, handler.group, attr)
+		return true
+	})
+
+	record := &Record{
+		Level:   LevelFromSlog(slogRecord.Level),
+		Time:    slogRecord.Time,
+		Prefix:  handler.log.prefix,
+		Message: slogRecord.Message,
+		Fields:  handler.log.fields.Merge(fields),
+		PC:      slogRecord.PC,
+		Context: ctx,
+	}
+
+	if record.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{record.PC}).Next()
+		record.File = frame.File
+		record.Line = frame.Line
+		record.Function = frame.Function
+	}
+
+	return handler.log.writeRecord(record)
+}
+
+// WithAttrs returns handler which writes records using child of the handler
+// log with given attributes as fields.
+func (handler *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := Fields{}
+	for _, attr := range attrs {
+		fields = appendSlogAttr(fields, handler.group, attr)
+	}
+
+	return &SlogHandler{
+		log:   handler.log.WithFields(fields),
+		group: handler.group,
+	}
+}
+
+// WithGroup returns handler which prefixes keys of all following attributes
+// with given group name.
+func (handler *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return handler
+	}
+
+	return &SlogHandler{
+		log:   handler.log,
+		group: handler.group + name + ".",
+	}
+}
+
+func appendSlogAttr(fields Fields, group string, attr slog.Attr) Fields {
+	attr.Value = attr.Value.Resolve()
+
+	if attr.Value.Kind() == slog.KindGroup {
+		attrs := attr.Value.Group()
+		if len(attrs) == 0 {
+			return fields
+		}
+
+		if attr.Key != "" {
+			group = group + attr.Key + "."
+		}
+
+		for _, attr := range attrs {
+			fields = appendSlogAttr(fields, group, attr)
+		}
+
+		return fields
+	}
+
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	return append(fields, Field{group + attr.Key, attr.Value.Any()})
+}
+
+// LevelFromSlog returns logging level which corresponds to given slog level,
+// slog levels between standard levels are rounded down to the less severe
+// level.
+func LevelFromSlog(level slog.Level) Level {
+	switch {
+	case level >= SlogLevelFatal:
+		return LevelFatal
+	case level >= slog.LevelError:
+		return LevelError
+	case level >= slog.LevelWarn:
+		return LevelWarning
+	case level >= slog.LevelInfo:
+		return LevelInfo
+	case level >= slog.LevelDebug:
+		return LevelDebug
+	}
+
+	return LevelTrace
+}
+
+// LevelToSlog returns slog level which corresponds to given logging level.
+func LevelToSlog(level Level) slog.Level {
+	switch level {
+	case LevelFatal:
+		return SlogLevelFatal
+	case LevelError:
+		return slog.LevelError
+	case LevelWarning:
+		return slog.LevelWarn
+	case LevelInfo:
+		return slog.LevelInfo
+	case LevelDebug:
+		return slog.LevelDebug
+	}
+
+	return SlogLevelTrace
+}
+
+// NewSlogLogger returns Logger which forwards all records to given
+// slog.Handler, Fatal and Fatalf call Exiter after handling record.
+func NewSlogLogger(handler slog.Handler) Logger {
+	return &slogLogger{handler: handler}
+}
+
+// ensure that slogLogger implements Logger interface.
+var _ Logger = (*slogLogger)(nil)
+
+type slogLogger struct {
+	handler slog.Handler
+}
+
+// slogLoggerCallStackLevel is the argument to runtime.Callers in
+// slogLogger.handle which points to the caller of logging function:
+// runtime.Callers <- handle <- log <- Info <- caller.
+const slogLoggerCallStackLevel = 4
+
+func (logger *slogLogger) log(level Level, value ...interface{}) {
+	if !logger.handler.Enabled(context.Background(), LevelToSlog(level)) {
+		return
+	}
+
+	logger.handle(level, fmt.Sprint(value...))
+}
+
+func (logger *slogLogger) logf(
+	level Level, format string, value ...interface{},
+) {
+	if !logger.handler.Enabled(context.Background(), LevelToSlog(level)) {
+		return
+	}
+
+	logger.handle(level, fmt.Sprintf(format, value...))
+}
+
+func (logger *slogLogger) handle(level Level, message string) {
+	var pcs [1]uintptr
+	runtime.Callers(slogLoggerCallStackLevel, pcs[:])
+
+	record := slog.NewRecord(time.Now(), LevelToSlog(level), message, pcs[0])
+
+	_ = logger.handler.Handle(context.Background(), record)
+}
+
+func (logger *slogLogger) Fatal(value ...interface{}) {
+	logger.log(LevelFatal, value...)
+	Exiter(1)
+}
+
+func (logger *slogLogger) Fatalf(format string, value ...interface{}) {
+	logger.logf(LevelFatal, format, value...)
+	Exiter(1)
+}
+
+func (logger *slogLogger) Error(value ...interface{}) {
+	logger.log(LevelError, value...)
+}
+
+func (logger *slogLogger) Errorf(format string, value ...interface{}) {
+	logger.logf(LevelError, format, value...)
+}
+
+func (logger *slogLogger) Warning(value ...interface{}) {
+	logger.log(LevelWarning, value...)
+}
+
+func (logger *slogLogger) Warningf(format string, value ...interface{}) {
+	logger.logf(LevelWarning, format, value...)
+}
+
+func (logger *slogLogger) Print(value ...interface{}) {
+	logger.log(LevelInfo, value...)
+}
+
+func (logger *slogLogger) Printf(format string, value ...interface{}) {
+	logger.logf(LevelInfo, format, value...)
+}
+
+func (logger *slogLogger) Info(value ...interface{}) {
+	logger.log(LevelInfo, value...)
+}
+
+func (logger *slogLogger) Infof(format string, value ...interface{}) {
+	logger.logf(LevelInfo, format, value...)
+}
+
+func (logger *slogLogger) Debug(value ...interface{}) {
+	logger.log(LevelDebug, value...)
+}
+
+func (logger *slogLogger) Debugf(format string, value ...interface{}) {
+	logger.logf(LevelDebug, format, value...)
+}
+
+func (logger *slogLogger) Trace(value ...interface{}) {
+	logger.log(LevelTrace, value...)
+}
+
+func (logger *slogLogger) Tracef(format string, value ...interface{}) {
+	logger.logf(LevelTrace, format, value...)
+}
@@ -0,0 +1,149 @@
+package lorg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/slogtest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlogHandler_Handle_WritesRecordsUsingLogFormat(t *testing.T) {
+	test := assert.New(t)
+
+	var buffer bytes.Buffer
+
+	log := NewLog()
+	log.SetOutput(&buffer)
+	log.SetFormat(NewFormat(`${level} ${prefix}%s${fields}`))
+	log.SetPrefix("app")
+
+	logger := slog.New(NewSlogHandler(log))
+	logger.Debug("hidden")
+	logger.Info("started", "port", 80)
+	logger.With("user", "alice").WithGroup("req").Warn(
+		"slow", "id", 1, slog.Group("timing", "total", "2s"),
+	)
+	logger.Log(context.Background(), SlogLevelTrace, "hidden")
+
+	test.Equal(
+		"INFO app started port=80\n"+
+			"WARNING app slow user=alice req.id=1 req.timing.total=2s\n",
+		buffer.String(),
+	)
+}
+
+func TestSlogHandler_SatisfiesSlogtest(t *testing.T) {
+	var buffer bytes.Buffer
+
+	log := NewLog()
+	log.SetOutput(&buffer)
+	log.SetFormat(NewJSONFormat())
+
+	err := slogtest.TestHandler(NewSlogHandler(log), func() []map[string]any {
+		var results []map[string]any
+
+		lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+		for _, line := range lines {
+			var object map[string]any
+			if err := json.Unmarshal([]byte(line), &object); err != nil {
+				t.Fatal(err)
+			}
+
+			result := map[string]any{
+				slog.LevelKey:   object["level"],
+				slog.MessageKey: object["message"],
+			}
+
+			if value, ok := object["time"]; ok {
+				result[slog.TimeKey] = value
+			}
+
+			// groups are flattened into field keys separated by dots
+			fields, _ := object["fields"].(map[string]any)
+			for key, value := range fields {
+				group := result
+				path := strings.Split(key, ".")
+				for _, name := range path[:len(path)-1] {
+					if _, ok := group[name].(map[string]any); !ok {
+						group[name] = map[string]any{}
+					}
+
+					group = group[name].(map[string]any)
+				}
+
+				group[path[len(path)-1]] = value
+			}
+
+			results = append(results, result)
+		}
+
+		return results
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSlogHandler_Enabled_FollowsLogLevel(t *testing.T) {
+	test := assert.New(t)
+
+	log := NewLog()
+	handler := NewSlogHandler(log)
+
+	test.False(handler.Enabled(context.Background(), slog.LevelDebug))
+
+	log.SetLevel(LevelTrace)
+	test.True(handler.Enabled(context.Background(), SlogLevelTrace))
+}
+
+func TestLevelFromSlog_IsInverseOfLevelToSlog(t *testing.T) {
+	test := assert.New(t)
+
+	for level := LevelFatal; level <= LevelTrace; level++ {
+		test.Equal(level, LevelFromSlog(LevelToSlog(level)))
+	}
+
+	test.Equal(LevelInfo, LevelFromSlog(slog.LevelInfo+1))
+	test.Equal(LevelTrace, LevelFromSlog(slog.LevelDebug-1))
+}
+
+func TestNewSlogLogger_ForwardsRecordsToHandler(t *testing.T) {
+	test := assert.New(t)
+
+	var buffer bytes.Buffer
+
+	handler := slog.NewTextHandler(&buffer, &slog.HandlerOptions{
+		AddSource: true,
+		Level:     slog.LevelDebug,
+		ReplaceAttr: func(_ []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			if attr.Key == slog.SourceKey {
+				source := attr.Value.Any().(*slog.Source)
+				return slog.String("func", source.Function)
+			}
+
+			return attr
+		},
+	})
+
+	logger := NewSlogLogger(handler)
+	logger.Trace("hidden")
+	logger.Debugf("value: %d", 1)
+	logger.Warning("warn")
+
+	test.Equal(
+		"level=DEBUG func=github.com/kovetskiy/lorg."+
+			"TestNewSlogLogger_ForwardsRecordsToHandler msg=\"value: 1\"\n"+
+			"level=WARN func=github.com/kovetskiy/lorg."+
+			"TestNewSlogLogger_ForwardsRecordsToHandler msg=warn\n",
+		buffer.String(),
+	)
+}
@@ -0,0 +1,217 @@
+package lorg
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// stackMaxDepth is the maximum amount of frames in captured stacks.
+const stackMaxDepth = 64
+
+// levelNone is the level of logger option which is disabled.
+const levelNone Level = -1
+
+var (
+	// lorgDirectory is the directory of lorg source files, frames of these
+	// files are removed from stacks.
+	lorgDirectory = func() string {
+		_, file, _, _ := runtime.Caller(0)
+		return filepath.Dir(file)
+	}()
+)
+
+// SetStackTraceLevel forces given logger to capture stack of the caller for
+// records with given level or more severe levels, so
+// SetStackTraceLevel(LevelError) captures stacks for LevelError and
+// LevelFatal records. Stack is saved to Record.Stack and can be rendered
+// using `${stack}` placeholder.
+//
+// If an error passed to logging function as an argument or a field provides
+// its own stack like errors of github.com/pkg/errors do, stack of the error
+// is used instead of stack of the caller, wrapped errors are checked too.
+//
+// Stack trace level is set for all children of given logger too.
+func (log *Log) SetStackTraceLevel(level Level) {
+	log.mutex.Lock()
+	log.setOptions(optionStackLevel, func(options *logOptions) {
+		options.stackLevel = level
+	})
+	log.mutex.Unlock()
+}
+
+// captureStack returns stack of error passed in values or fields of given
+// record or stack of the caller at given depth, stack is not captured if
+// record level is less severe than stack trace level of given logger. Stack
+// of the caller is not captured if skip is negative.
+func (log *Log) captureStack(
+	record *Record, values []interface{}, skip int,
+) []runtime.Frame {
+	stackLevel := log.getOptions().stackLevel
+	if stackLevel == levelNone || record.Level > stackLevel {
+		return nil
+	}
+
+	for _, value := range values {
+		if err, ok := value.(error); ok {
+			if stack := errorStack(err); stack != nil {
+				return stackFrames(stack)
+			}
+		}
+	}
+
+	for _, field := range record.Fields {
+		if err, ok := field.Value.(error); ok {
+			if stack := errorStack(err); stack != nil {
+				return stackFrames(stack)
+			}
+		}
+	}
+
+	if skip < 0 {
+		return nil
+	}
+
+	pcs := make([]uintptr, stackMaxDepth)
+
+	return stackFrames(pcs[:runtime.Callers(skip+2, pcs)])
+}
+
+// errorStack returns program counters of the stack which is provided by the
+// deepest error in chain of given error.
+func errorStack(err error) []uintptr {
+	switch wrapped := err.(type) {
+	case interface{ Unwrap() []error }:
+		for _, err := range wrapped.Unwrap() {
+			if stack := errorStack(err); stack != nil {
+				return stack
+			}
+		}
+
+	default:
+		if wrapped := errors.Unwrap(err); wrapped != nil {
+			if stack := errorStack(wrapped); stack != nil {
+				return stack
+			}
+		}
+	}
+
+	return stackTrace(err)
+}
+
+// stackTrace returns program counters returned by StackTrace or Callers
+// method of given error, methods should return slice of program counters,
+// but slice element type can be named, like errors.Frame of
+// github.com/pkg/errors.
+func stackTrace(err error) []uintptr {
+	if err == nil {
+		return nil
+	}
+
+	value := reflect.ValueOf(err)
+
+	for _, method := range []reflect.Value{
+		value.MethodByName("StackTrace"),
+		value.MethodByName("Callers"),
+	} {
+		if !method.IsValid() ||
+			method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+			continue
+		}
+
+		result := method.Type().Out(0)
+		if result.Kind() != reflect.Slice ||
+			result.Elem().Kind() != reflect.Uintptr {
+			continue
+		}
+
+		frames := method.Call(nil)[0]
+
+		pcs := make([]uintptr, frames.Len())
+		for index := range pcs {
+			pcs[index] = uintptr(frames.Index(index).Uint())
+		}
+
+		return pcs
+	}
+
+	return nil
+}
+
+// stackFrames resolves given program counters and removes frames of lorg,
+// helper functions and runtime.
+func stackFrames(pcs []uintptr) []runtime.Frame {
+	if len(pcs) == 0 {
+		return nil
+	}
+
+	var stack []runtime.Frame
+
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if !isStackNoise(frame) {
+			stack = append(stack, frame)
+		}
+
+		if !more {
+			return stack
+		}
+	}
+}
+
+func isStackNoise(frame runtime.Frame) bool {
+	if strings.HasPrefix(frame.Function, "runtime.") {
+		return true
+	}
+
+	if filepath.Dir(frame.File) == lorgDirectory &&
+		!strings.HasSuffix(frame.File, "_test.go") {
+		return true
+	}
+
+	return isHelper(frame.Function)
+}
+
+// RecordPlaceholderStack returns stack of the record captured by logger, see
+// Log.SetStackTraceLevel, every frame is rendered on separate line
+// prepended by tab: "\n\tmain.main main.go:12". Empty string is returned if
+// record has no stack. File names are rendered in the same modes as
+// RecordPlaceholderFile.
+//
+// Using: %s${stack}
+//
+//	%s${stack:long}
+func RecordPlaceholderStack(record *Record, mode string) string {
+	if len(record.Stack) == 0 {
+		return ""
+	}
+
+	var builder strings.Builder
+	for _, line := range formatStack(record.Stack, mode) {
+		builder.WriteString("\n\t")
+		builder.WriteString(line)
+	}
+
+	return builder.String()
+}
+
+// formatStack returns frames of given stack in compact form:
+// "main.main main.go:12".
+func formatStack(stack []runtime.Frame, mode string) []string {
+	lines := make([]string, len(stack))
+	for index, frame := range stack {
+		function := frame.Function
+		if function == "" {
+			function = "??"
+		}
+
+		lines[index] = function + " " + formatFile(frame.File, mode) + ":" +
+			strconv.Itoa(frame.Line)
+	}
+
+	return lines
+}
@@ -0,0 +1,181 @@
+package lorg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stackFrame mimics errors.Frame of github.com/pkg/errors.
+type stackFrame uintptr
+
+type stackError struct {
+	message string
+	stack   []uintptr
+}
+
+func newStackError(message string) error {
+	pcs := make([]uintptr, 32)
+
+	return &stackError{
+		message: message,
+		stack:   pcs[:runtime.Callers(2, pcs)],
+	}
+}
+
+func (err *stackError) Error() string {
+	return err.message
+}
+
+func (err *stackError) StackTrace() []stackFrame {
+	frames := make([]stackFrame, len(err.stack))
+	for index, pc := range err.stack {
+		frames[index] = stackFrame(pc)
+	}
+
+	return frames
+}
+
+func TestLog_SetStackTraceLevel_CapturesStackOfCaller(t *testing.T) {
+	test := assert.New(t)
+
+	log := NewLog()
+	buffer := &bytes.Buffer{}
+	log.SetOutput(buffer)
+	log.SetFormat(NewFormat(`%s${stack}`))
+	log.SetStackTraceLevel(LevelError)
+
+	log.Warning("warning")
+	test.Equal("warning\n", buffer.String())
+
+	buffer.Reset()
+	log.Error("error")
+
+	lines := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+
+	test.Equal("error", lines[0])
+	test.Regexp(
+		`^\tgithub.com/kovetskiy/lorg.TestLog_SetStackTraceLevel_`+
+			`CapturesStackOfCaller stack_test.go:\d+$`,
+		lines[1],
+	)
+	test.Regexp(`^\ttesting.tRunner testing.go:\d+$`, lines[2])
+
+	for _, line := range lines[1:] {
+		test.NotContains(line, "runtime.")
+		test.NotContains(line, "(*Log)")
+	}
+}
+
+func TestLog_SetStackTraceLevel_UsesStackOfWrappedErrors(t *testing.T) {
+	test := assert.New(t)
+
+	log := NewLog()
+	buffer := &bytes.Buffer{}
+	log.SetOutput(buffer)
+	log.SetFormat(NewFormat(`%s${stack}`))
+	log.SetStackTraceLevel(LevelError)
+
+	err := fmt.Errorf("can't connect: %w", newStackError("refused"))
+
+	testcases := []func(){
+		func() { log.Error(err) },
+		func() { log.Errorf("failed: %s", err) },
+		func() { log.Errorw("failed", "error", err) },
+		func() { log.With("error", errors.Join(errors.New("a"), err)).Error("b") },
+	}
+
+	for index, testcase := range testcases {
+		buffer.Reset()
+		testcase()
+
+		lines := strings.Split(buffer.String(), "\n")
+		test.Regexp(
+			`^\tgithub.com/kovetskiy/lorg.TestLog_SetStackTraceLevel_`+
+				`UsesStackOfWrappedErrors stack_test.go:\d+$`,
+			lines[1],
+			"testcase #%d", index,
+		)
+	}
+}
+
+func TestLog_SetStackTraceLevel_IsRenderedByRecordFormatters(t *testing.T) {
+	test := assert.New(t)
+
+	log := NewLog()
+	buffer := &bytes.Buffer{}
+	log.SetOutput(buffer)
+	log.SetStackTraceLevel(LevelFatal)
+	log.SetExiter(func(int) {})
+
+	log.SetFormat(NewJSONFormat())
+	log.Fatal("json")
+
+	test.Regexp(
+		`"stack":\["github.com/kovetskiy/lorg.TestLog_SetStackTraceLevel_`+
+			`IsRenderedByRecordFormatters stack_test.go:\d+",`,
+		buffer.String(),
+	)
+
+	buffer.Reset()
+
+	log.SetFormat(NewLogfmtFormat())
+	log.Fatal("logfmt")
+
+	test.Regexp(
+		`stack="github.com/kovetskiy/lorg.TestLog_SetStackTraceLevel_`+
+			`IsRenderedByRecordFormatters stack_test.go:\d+; testing.tRunner`,
+		buffer.String(),
+	)
+}
+
+func TestLog_SetStackTraceLevel_IsSetForExistingChildren(t *testing.T) {
+	test := assert.New(t)
+
+	log := NewLog()
+	buffer := &bytes.Buffer{}
+	log.SetOutput(buffer)
+	log.SetFormat(NewFormat(`%s${stack}`))
+
+	child := log.Named("db")
+	log.SetStackTraceLevel(LevelError)
+
+	child.Error("error")
+
+	test.Contains(buffer.String(), "\n\tgithub.com/kovetskiy/lorg.TestLog_")
+}
+
+func TestLog_SetStackTraceLevel_IsSafeForConcurrentLogging(t *testing.T) {
+	log := NewLog()
+	log.SetOutput(&bytes.Buffer{})
+
+	child := log.Named("db")
+
+	group := &sync.WaitGroup{}
+	group.Add(2)
+
+	go func() {
+		defer group.Done()
+
+		for i := 0; i < 1000; i++ {
+			log.SetStackTraceLevel(LevelError)
+			log.SetStackTraceLevel(levelNone)
+		}
+	}()
+
+	go func() {
+		defer group.Done()
+
+		for i := 0; i < 1000; i++ {
+			child.Error("concurrent")
+		}
+	}()
+
+	group.Wait()
+}
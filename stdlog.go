@@ -0,0 +1,147 @@
+package lorg
+
+import (
+	"bytes"
+	"io"
+	stdlog "log"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// NewStdLogger returns standard library logger which writes every line as a
+// record with given level to given log, so records are formatted by Log
+// format and written to Log output.
+func NewStdLogger(log *Log, level Level) *stdlog.Logger {
+	return stdlog.New(log.Writer(level), "", 0)
+}
+
+// RedirectStdLog forces the global standard library logger to write every
+// line as a record with given level to given log. RedirectStdLog returns
+// function which restores previous output, flags and prefix of the standard
+// library logger.
+func RedirectStdLog(log *Log, level Level) func() {
+	var (
+		flags  = stdlog.Flags()
+		prefix = stdlog.Prefix()
+		output = stdlog.Writer()
+	)
+
+	stdlog.SetFlags(0)
+	stdlog.SetPrefix("")
+	stdlog.SetOutput(log.Writer(level))
+
+	return func() {
+		stdlog.SetFlags(flags)
+		stdlog.SetPrefix(prefix)
+		stdlog.SetOutput(output)
+	}
+}
+
+// stdLogCallerDepth is the argument to caller in logWriter.Write which points
+// to the caller of the standard library logger:
+// Write <- Logger.output <- Logger.Printf <- caller.
+const stdLogCallerDepth = 3
+
+// Writer returns io.WriteCloser which splits written data on newlines and
+// writes every non-empty line as a record with given level to given log.
+//
+// Data after the last newline is buffered until the next newline, so lines
+// written in chunks, for example, by io.Copy, are written as whole records.
+// Buffered data is written by Flush or Close.
+//
+// If writer is used as output of the standard library logger, records have
+// the caller of the standard library logger as the caller of logging
+// function, see NewStdLogger.
+func (log *Log) Writer(level Level) io.WriteCloser {
+	return &logWriter{log: log, level: level, mutex: &sync.Mutex{}}
+}
+
+type logWriter struct {
+	log    *Log
+	level  Level
+	buffer []byte
+	mutex  *sync.Mutex
+}
+
+func (writer *logWriter) Write(data []byte) (int, error) {
+	if writer.log.GetLevel() < writer.level {
+		return len(data), nil
+	}
+
+	// caller should be determined right here, because it uses fixed stack
+	// depth
+	frame := caller(stdLogCallerDepth)
+
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+
+	writer.buffer = append(writer.buffer, data...)
+
+	end := bytes.LastIndexByte(writer.buffer, '\n')
+	if end < 0 {
+		return len(data), nil
+	}
+
+	lines := writer.buffer[:end]
+
+	var err error
+	for _, line := range bytes.Split(lines, []byte{'\n'}) {
+		err = writer.writeLine(line, frame)
+		if err != nil {
+			break
+		}
+	}
+
+	writer.buffer = append(writer.buffer[:0], writer.buffer[end+1:]...)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return len(data), nil
+}
+
+// Flush writes buffered data which is not terminated by newline as a record.
+func (writer *logWriter) Flush() error {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+
+	if len(writer.buffer) == 0 {
+		return nil
+	}
+
+	line := writer.buffer
+	writer.buffer = nil
+
+	if writer.log.GetLevel() < writer.level {
+		return nil
+	}
+
+	return writer.writeLine(line, runtime.Frame{})
+}
+
+// Close is the same as Flush.
+func (writer *logWriter) Close() error {
+	return writer.Flush()
+}
+
+// writeLine writes given line as a record, writer mutex should be locked.
+func (writer *logWriter) writeLine(line []byte, frame runtime.Frame) error {
+	line = bytes.TrimSuffix(line, []byte{'\r'})
+	if len(line) == 0 {
+		return nil
+	}
+
+	return writer.log.writeRecord(&Record{
+		Level:    writer.level,
+		Time:     time.Now(),
+		Prefix:   writer.log.prefix,
+		Message:  string(line),
+		Fields:   writer.log.fields,
+		PC:       frame.PC,
+		File:     frame.File,
+		Line:     frame.Line,
+		Function: frame.Function,
+	})
+}
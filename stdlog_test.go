@@ -0,0 +1,111 @@
+package lorg
+
+import (
+	"bytes"
+	"io"
+	stdlog "log"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLog_Writer_WritesEveryLineAsRecord(t *testing.T) {
+	test := assert.New(t)
+
+	var buffer bytes.Buffer
+
+	log := NewLog()
+	log.SetOutput(&buffer)
+	log.SetFormat(NewFormat(`${level} %s`))
+
+	writer := log.Writer(LevelWarning)
+
+	written, err := writer.Write([]byte("1\n\n2\r\n3"))
+	test.NoError(err)
+	test.Equal(7, written)
+
+	_, err = log.Writer(LevelDebug).Write([]byte("hidden\n"))
+	test.NoError(err)
+
+	test.Equal("WARNING 1\nWARNING 2\n", buffer.String())
+
+	test.NoError(writer.Close())
+	test.Equal("WARNING 1\nWARNING 2\nWARNING 3\n", buffer.String())
+}
+
+func TestLog_Writer_BuffersPartialLines(t *testing.T) {
+	test := assert.New(t)
+
+	var buffer bytes.Buffer
+
+	log := NewLog()
+	log.SetOutput(&buffer)
+	log.SetFormat(NewFormat(`%s`))
+
+	writer := log.Writer(LevelInfo)
+
+	_, err := io.Copy(
+		writer, iotest.OneByteReader(strings.NewReader("partial line\nne")),
+	)
+	test.NoError(err)
+	test.Equal("partial line\n", buffer.String())
+
+	_, err = writer.Write([]byte("xt\n"))
+	test.NoError(err)
+	test.Equal("partial line\nnext\n", buffer.String())
+}
+
+func TestNewStdLogger_WritesToLog(t *testing.T) {
+	test := assert.New(t)
+
+	var buffer bytes.Buffer
+
+	log := NewLog()
+	log.SetOutput(&buffer)
+	log.SetFormat(NewFormat(`${level} ${prefix}%s`))
+	log.SetPrefix("lib")
+
+	NewStdLogger(log, LevelError).Printf("failed: %s", "timeout")
+
+	test.Equal("ERROR lib failed: timeout\n", buffer.String())
+
+	buffer.Reset()
+	log.SetFormat(NewFormat(`${file}:${line} %s`))
+
+	_, _, line, _ := runtime.Caller(0)
+	NewStdLogger(log, LevelError).Print("caller")
+
+	test.Equal(
+		"stdlog_test.go:"+strconv.Itoa(line+1)+" caller\n", buffer.String(),
+	)
+}
+
+func TestRedirectStdLog_RedirectsAndRestoresStdLogger(t *testing.T) {
+	test := assert.New(t)
+
+	var buffer bytes.Buffer
+	var previous bytes.Buffer
+
+	stdlog.SetOutput(&previous)
+	stdlog.SetFlags(0)
+
+	log := NewLog()
+	log.SetOutput(&buffer)
+	log.SetFormat(NewFormat(`${level} %s`))
+
+	restore := RedirectStdLog(log, LevelInfo)
+	stdlog.Print("redirected")
+	restore()
+	stdlog.Print("restored")
+
+	test.Equal("INFO redirected\n", buffer.String())
+	test.Equal("restored\n", previous.String())
+
+	stdlog.SetOutput(os.Stderr)
+	stdlog.SetFlags(stdlog.LstdFlags)
+}